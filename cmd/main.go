@@ -5,6 +5,7 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -12,15 +13,19 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/open-dovetail/eth-track/export"
 	"github.com/open-dovetail/eth-track/proc"
 	"github.com/open-dovetail/eth-track/redshift"
 )
 
 type Config struct {
 	nodeURL        string // Ethereum node URL
-	apiKey         string // etherscan API key
+	apiKey         string // etherscan API keys separated by comma
 	etherscanDelay int    // delay of consecutive etherscan API invocation in ms
+	etherscanLimit int    // max etherscan API calls per key per day
+	factoryConfig  string // JSON file of factory templates for ABI of child contracts
 	blockDelay     int    // blockchain height delay for last confirmed block
+	flattenParams  bool   // true to append components of tuple and array params with dotted names
 	threads        int    // number of threads for processing blocks
 	batchSize      int    // size of block interval per worker job
 	awsProfile     string // profile name for AWS user
@@ -29,7 +34,28 @@ type Config struct {
 	awsRedshift    string // redshift DB name
 	awsS3Bucket    string // name of AWS s3 bucket
 	awsCopyRole    string // aws role for copying csv from s3 to redshift
+	copyJobs       int    // number of worker jobs combined in one redshift copy
+	gzip           bool   // true to compress csv files staged in s3
+	stageParquet   bool   // true to stage files in s3 as Parquet instead of csv
+	keepStaged     bool   // true to keep staged files in s3 as an archive
+	s3Endpoint     string // URL of s3-compatible object store of parquetBucket, blank for AWS s3
+	s3PathStyle    bool   // true to address parquetBucket in URL path of s3-compatible object store
+	chain          string // name of the blockchain in exported data
+	parquetDir     string // local folder of exported Parquet files
+	parquetBucket  string // s3 bucket of exported Parquet files, separate from the bucket of staged files
+	parquetPrefix  string // folder of exported Parquet files in parquetBucket
+	ndjson         string // file path or named pipe of exported NDJSON, or - for stdout
+	ndjsonMaxMB    int    // max size in MB of NDJSON file before rotation, 0 to disable
+	natsURL        string // URL of NATS server to publish decoded transactions and events
+	natsTxSubject  string // subject template of transactions published to NATS
+	natsLogSubject string // subject template of event logs published to NATS
+	natsJetStream  bool   // true to publish to NATS JetStream with acks, false for core NATS without delivery guarantee
+	sinkOnly       bool   // true to export blocks only to sinks without redshift database
+	progressFile   string // local JSON file of block progress in sink-only mode
+	contractFile   string // local NDJSON file of contracts in sink-only mode
 	oldBlocks      bool   // true to collect old blocks
+	tokenRefresh   int    // interval in minutes to refresh metadata and supply of active tokens, 0 to disable, ignored in sink-only mode
+	tokenDays      int    // refresh tokens with events in the recent days
 }
 
 var config = &Config{}
@@ -37,9 +63,12 @@ var config = &Config{}
 // Initial values of the command-line args
 func init() {
 	flag.StringVar(&config.nodeURL, "nodeURL", "http://localhost:8545", "Ethereum node URL")
-	flag.StringVar(&config.apiKey, "apiKey", "", "Etherscan API key")
-	flag.IntVar(&config.etherscanDelay, "etherscanDelay", 350, "delay in millis between etherscan API calls")
+	flag.StringVar(&config.apiKey, "apiKey", "", "Etherscan API keys separated by comma")
+	flag.IntVar(&config.etherscanDelay, "etherscanDelay", 350, "delay in millis between etherscan API calls of each key")
+	flag.IntVar(&config.etherscanLimit, "etherscanLimit", 100000, "max etherscan API calls per key per day, 0 for unlimited")
+	flag.StringVar(&config.factoryConfig, "factoryConfig", "", "JSON file of factory templates for ABI of child contracts")
 	flag.IntVar(&config.blockDelay, "blockDelay", 12, "blockchain height delay for last confirmed block")
+	flag.BoolVar(&config.flattenParams, "flattenParams", false, "append components of tuple and array params with dotted names, e.g., order.maker, path[0]")
 	flag.IntVar(&config.threads, "threads", 5, "number of threads for processing blocks")
 	flag.IntVar(&config.batchSize, "batchSize", 40, "size of block interval per worker job")
 	flag.StringVar(&config.awsProfile, "profile", "default", "profile name for AWS user")
@@ -48,7 +77,28 @@ func init() {
 	flag.StringVar(&config.awsRedshift, "redshift", "ethdb", "Redshift database name")
 	flag.StringVar(&config.awsS3Bucket, "s3Bucket", "dev-eth-track", "AWS s3 bucket name")
 	flag.StringVar(&config.awsCopyRole, "copyRole", "", "AWS role to copy csv from s3 to redshift")
+	flag.IntVar(&config.copyJobs, "copyJobs", 1, "number of worker jobs combined in one redshift copy using manifests, 1 to copy each job separately")
+	flag.BoolVar(&config.gzip, "gzip", false, "compress csv files staged in s3 using gzip")
+	flag.BoolVar(&config.stageParquet, "stageParquet", false, "stage files in s3 as Parquet instead of csv, and copy them to redshift using FORMAT AS PARQUET")
+	flag.BoolVar(&config.keepStaged, "keepStaged", false, "keep staged files in s3 as an archive after copy to redshift")
+	flag.StringVar(&config.s3Endpoint, "s3Endpoint", "", "URL of s3-compatible object store of -parquetBucket, e.g., http://localhost:9000 for MinIO, blank for AWS s3")
+	flag.BoolVar(&config.s3PathStyle, "s3PathStyle", false, "address -parquetBucket in URL path of s3-compatible object store")
+	flag.StringVar(&config.chain, "chain", "ethereum", "name of the blockchain in exported data")
+	flag.StringVar(&config.parquetDir, "parquetDir", "", "local folder to export blocks as Parquet files partitioned by chain and date")
+	flag.StringVar(&config.parquetBucket, "parquetBucket", "", "s3 bucket to export blocks as Parquet files partitioned by chain and date, separate from -s3Bucket of staged files")
+	flag.StringVar(&config.parquetPrefix, "parquetPrefix", "", "folder in -parquetBucket of exported Parquet files")
+	flag.StringVar(&config.ndjson, "ndjson", "", "file path or named pipe to export blocks as NDJSON, or - for stdout")
+	flag.IntVar(&config.ndjsonMaxMB, "ndjsonMaxMB", 0, "max size in MB of NDJSON file before rotation, 0 to disable")
+	flag.StringVar(&config.natsURL, "natsURL", "", "NATS server URL to publish decoded transactions and events, e.g., nats://localhost:4222")
+	flag.StringVar(&config.natsTxSubject, "natsTxSubject", "eth.txs.<address>.<method>", "NATS subject template of transactions with placeholders <address>, <method> and <standard>, blank to disable")
+	flag.StringVar(&config.natsLogSubject, "natsLogSubject", "eth.logs.<address>.<event>", "NATS subject template of event logs with placeholders <address>, <event> and <standard>, blank to disable")
+	flag.BoolVar(&config.natsJetStream, "natsJetStream", true, "publish to NATS JetStream with acks for at-least-once delivery, false to publish to core NATS without delivery guarantee")
+	flag.BoolVar(&config.sinkOnly, "sinkOnly", false, "export blocks only to Parquet, NDJSON or NATS sinks without redshift database")
+	flag.StringVar(&config.progressFile, "progressFile", "progress.json", "local JSON file of block progress in sink-only mode")
+	flag.StringVar(&config.contractFile, "contractFile", "contracts.ndjson", "local NDJSON file of contracts in sink-only mode, so ABIs are not fetched from etherscan again after restart")
 	flag.BoolVar(&config.oldBlocks, "oldBlocks", false, "Collect old blocks")
+	flag.IntVar(&config.tokenRefresh, "tokenRefresh", 1440, "interval in minutes to refresh metadata and supply of active tokens starting at startup, 0 to disable, ignored with -sinkOnly")
+	flag.IntVar(&config.tokenDays, "tokenDays", 7, "refresh tokens with events in the recent days")
 }
 
 // check env variables, which overrides the commandline input
@@ -142,32 +192,96 @@ func main() {
 		return schedule(job, sig, ctx)
 	})
 
+	// start writer that copies staged jobs of workers to redshift at once
+	if config.copyJobs > 1 {
+		g.Go(func() error {
+			return flushBlocks(sig, ctx)
+		})
+	}
+
+	// start token refresher, which is not used without database
+	if config.tokenRefresh > 0 && !config.sinkOnly {
+		g.Go(func() error {
+			return refreshTokens(sig, ctx)
+		})
+	} else if config.tokenRefresh > 0 {
+		glog.Info("token refresher is disabled in sink-only mode")
+	}
+
 	// wait for scheduler and all workers to exit
 	if err := g.Wait(); err != nil {
 		glog.Infof("Failed from a processing thread: %v", err)
 	}
+	if config.copyJobs > 1 {
+		// workers have stopped, so copy or clean up their pending staged jobs
+		if err := redshift.GetBlockWriter(config.copyJobs).Close(); err != nil {
+			glog.Errorf("Failed to copy pending staged jobs: %+v", err)
+		}
+	}
+	// store new contracts that are not saved in a full batch yet
+	if err := proc.SaveContracts(); err != nil {
+		glog.Errorf("Failed to save new contracts: %+v", err)
+	}
+	proc.CloseBlockSinks()
 	glog.Flush()
 }
 
-// initialize connections of Ethereum, etherscan and redshift
+// initialize connections of Ethereum, etherscan and redshift, or only sinks of decoded blocks in sink-only mode
 func connect() error {
+	// s3-compatible object store is used only for exported Parquet files, since redshift copies staged files only from AWS s3
+	if len(config.parquetBucket) == 0 && (len(config.s3Endpoint) > 0 || len(config.parquetPrefix) > 0) {
+		return errors.New("-s3Endpoint and -parquetPrefix require -parquetBucket")
+	}
+
 	// initialize ethereum node client
 	if _, err := proc.NewEthereumClient(config.nodeURL); err != nil {
 		return errors.Wrapf(err, "Failed to connect to ethereum node %s", config.nodeURL)
 	}
 	proc.SetBlockDelay(config.blockDelay)
+	proc.SetFlattenParams(config.flattenParams)
 
 	// initialize etherscan api connection
-	proc.ConfigEtherscan(config.apiKey, config.etherscanDelay)
+	proc.ConfigEtherscanKeys(strings.Split(config.apiKey, ","), config.etherscanDelay, config.etherscanLimit)
+	if len(config.factoryConfig) > 0 {
+		if err := proc.LoadFactoryTemplates(config.factoryConfig); err != nil {
+			return err
+		}
+	}
 	dai := "0x6b175474e89094c44da98b954eedeac495271d0f"
 	if _, err := proc.FetchABI(dai, 0); err != nil {
 		return errors.Wrapf(err, "Failed to invoke etherscan API with key %s", config.apiKey)
 	}
 
-	// config AWS s3 bucket
+	if config.sinkOnly {
+		// progress and contracts are tracked in local files since there is no database
+		redshift.SetProgressFile(config.progressFile)
+		if err := redshift.SetContractFile(config.contractFile); err != nil {
+			return err
+		}
+		config.copyJobs = 1
+		if len(config.parquetDir) == 0 && len(config.parquetBucket) == 0 && len(config.ndjson) == 0 && len(config.natsURL) == 0 {
+			return errors.New("sink-only mode requires -parquetDir, -parquetBucket, -ndjson or -natsURL")
+		}
+		return addBlockSinks()
+	}
+
+	if config.gzip && config.stageParquet {
+		return errors.New("-gzip applies to csv files only, and cannot be used with -stageParquet")
+	}
 	if _, err := redshift.GetS3Bucket(config.awsS3Bucket, config.awsProfile, config.awsRegion, config.awsCopyRole); err != nil {
 		return errors.Wrapf(err, "Failed to config AWS s3 bucket %s", config.awsS3Bucket)
 	}
+	redshift.SetStagingGzip(config.gzip)
+	redshift.SetStagingParquet(config.stageParquet)
+	redshift.SetKeepStagedFiles(config.keepStaged)
+	if config.copyJobs > 1 {
+		proc.SetBlockWriter(redshift.GetBlockWriter(config.copyJobs))
+	}
+
+	// initialize export of decoded blocks
+	if err := addBlockSinks(); err != nil {
+		return err
+	}
 
 	// initialize redshift db connection
 	secret, err := redshift.GetAWSSecret(config.awsSecret, config.awsProfile, config.awsRegion)
@@ -184,6 +298,39 @@ func connect() error {
 	return nil
 }
 
+// add sinks of decoded blocks for configured exports
+func addBlockSinks() error {
+	if len(config.parquetDir) > 0 {
+		store, err := redshift.NewLocalStore(config.parquetDir)
+		if err != nil {
+			return err
+		}
+		proc.AddBlockSink(export.NewParquetSink(store, "", config.chain))
+	}
+	if len(config.parquetBucket) > 0 {
+		store, err := redshift.NewS3Bucket(config.parquetBucket, config.s3Endpoint, config.awsProfile, config.awsRegion, config.s3PathStyle)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to config s3 bucket %s of Parquet files", config.parquetBucket)
+		}
+		proc.AddBlockSink(export.NewParquetSink(store, config.parquetPrefix, config.chain))
+	}
+	if len(config.ndjson) > 0 {
+		sink, err := export.NewNDJSONSink(config.ndjson, int64(config.ndjsonMaxMB)*1024*1024)
+		if err != nil {
+			return err
+		}
+		proc.AddBlockSink(sink)
+	}
+	if len(config.natsURL) > 0 {
+		sink, err := export.NewNATSSink(config.natsURL, config.natsTxSubject, config.natsLogSubject, config.natsJetStream)
+		if err != nil {
+			return err
+		}
+		proc.AddBlockSink(sink)
+	}
+	return nil
+}
+
 // continuously create block processing jobs until os interrupt is received
 // each job is created as a block interval on the output channel
 func schedule(job chan<- redshift.Interval, sig <-chan os.Signal, ctx context.Context) error {
@@ -295,6 +442,63 @@ func addBatchJob(v redshift.Interval, jobs []redshift.Interval) []redshift.Inter
 	return result
 }
 
+// periodically refresh metadata and supply snapshots of active tokens until os interrupt is received
+func refreshTokens(sig <-chan os.Signal, ctx context.Context) error {
+	glog.Info("token refresher started")
+	// refresh at startup, so tokens are not stale for a full interval after restart
+	refreshActiveTokens()
+	ticker := time.NewTicker(time.Duration(config.tokenRefresh) * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			glog.Infof("token refresher returns %v", ctx.Err())
+			return ctx.Err()
+		case <-sig:
+			glog.Info("token refresher received os interrupt")
+			return errors.New("interrupted")
+		case <-ticker.C:
+			refreshActiveTokens()
+		}
+	}
+}
+
+// refresh metadata and supply of tokens with events in the recent tokenDays
+func refreshActiveTokens() {
+	minEventTime := time.Now().Unix() - int64(config.tokenDays*24*60*60)
+	if _, err := proc.RefreshTokens(minEventTime); err != nil {
+		// refresh again in the next cycle
+		glog.Warningf("Failed to refresh tokens: %+v", err)
+	}
+}
+
+// periodically copy staged jobs to redshift, so jobs do not wait for a full batch when workers are idle.
+// pending jobs at exit are copied by main after all workers stopped, since workers may still stage jobs when this returns.
+func flushBlocks(sig <-chan os.Signal, ctx context.Context) error {
+	glog.Info("block writer started")
+	writer := redshift.GetBlockWriter(config.copyJobs)
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			glog.Infof("block writer returns %v", ctx.Err())
+			return ctx.Err()
+		case <-sig:
+			glog.Info("block writer received os interrupt")
+			return errors.New("interrupted")
+		case <-ticker.C:
+			if writer.Pending() == 0 {
+				continue
+			}
+			if err := writer.Flush(); err != nil {
+				glog.Infof("block writer returns error %v", err)
+				return err
+			}
+		}
+	}
+}
+
 // continuously receive jobs from input channel.
 // returns error if process failed or ctx closed by other worker when used with sync.errgroup.
 func work(gid int, job <-chan redshift.Interval, sig <-chan os.Signal, ctx context.Context) error {
@@ -312,10 +516,30 @@ func work(gid int, job <-chan redshift.Interval, sig <-chan os.Signal, ctx conte
 			return errors.New("interrupted")
 		case v := <-job:
 			glog.Infof("worker %d processing block interval [%d, %d]", gid, v.Low, v.High)
-			if err := proc.DecodeBlockRange(v.High, v.Low); err != nil {
+			err := proc.DecodeBlockRange(v.High, v.Low)
+			for errors.Is(err, proc.ErrBudgetExceeded) {
+				// decode the block interval again after the daily budget of etherscan API keys is reset
+				reset := proc.EtherscanBudgetReset()
+				glog.Warningf("worker %d defers block interval [%d, %d] till %v: %v", gid, v.Low, v.High, reset, err)
+				select {
+				case <-ctx.Done():
+					glog.Infof("worker %d returns %v", gid, ctx.Err())
+					return ctx.Err()
+				case <-sig:
+					glog.Infof("worker %d received os interrupt", gid)
+					return errors.New("interrupted")
+				case <-time.After(time.Until(reset)):
+				}
+				err = proc.DecodeBlockRange(v.High, v.Low)
+			}
+			if err != nil {
 				glog.Infof("worker %d returns error %v", gid, err)
 				return err
 			}
+			if config.copyJobs > 1 {
+				// progress is saved by block writer after staged jobs are copied
+				continue
+			}
 			for i := v.Low; i <= v.High; i++ {
 				blockCache.AddBlock(i)
 			}
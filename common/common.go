@@ -1,7 +1,10 @@
 package common
 
 import (
+	"encoding/hex"
 	"math/big"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +13,13 @@ import (
 	"github.com/umbracle/ethgo/abi"
 )
 
+// sink of decoded blocks, e.g., file export or message bus.
+// blocks are written before progress of the block range is saved, so a failed write causes the range to be decoded again.
+type BlockSink interface {
+	WriteBlocks(blocks map[string]*Block) error
+	Close() error
+}
+
 type Iterator interface {
 	// points to the next item. returns true if there is another item.
 	Next() bool
@@ -25,17 +35,56 @@ type NamedValue struct {
 	Value interface{}
 }
 
+type AddressType int16
+
+const (
+	UnknownAddress AddressType = iota
+	ContractAddress
+	EOAAddress // externally owned account
+)
+
+func (a AddressType) String() string {
+	return [...]string{"unknown", "contract", "eoa"}[a]
+}
+
+// operations of inner calls
+const (
+	CallOperation         = "CALL"
+	DelegateCallOperation = "DELEGATECALL"
+)
+
 type Contract struct {
-	Address       string
-	Name          string
-	Symbol        string
-	Decimals      uint8
-	TotalSupply   float64
-	LastEventDate int64  // last collected event date
-	LastErrorDate int64  // last block time when tx/log parsing failed
-	ABI           string // ABI from etherscan; blank if failed to parse
-	Methods       map[string]*abi.Method
-	Events        map[string]*abi.Event
+	Address        string
+	AddressType    AddressType // contract or EOA, unknown if not checked
+	Name           string
+	Symbol         string
+	Decimals       uint8
+	TotalSupply    float64
+	ExactSupply    *big.Int
+	LastEventDate  int64  // last collected event date
+	LastErrorDate  int64  // last block time when tx/log parsing failed
+	ABI            string // ABI from etherscan; blank if failed to parse
+	ContractName   string // contract name of verified source code on etherscan
+	Compiler       string // compiler version of verified source code
+	Optimized      bool   // true if compiled with optimizer enabled
+	OptimizerRuns  int    // optimizer runs setting of the compiler
+	License        string // license type of verified source code
+	IsProxy        bool   // true if etherscan identifies the contract as a proxy
+	Implementation string // implementation address of a proxy contract
+	CodeHash       string // keccak256 hash of runtime code in hex
+	CloneTarget    string // target address if the contract is an EIP-1167 minimal proxy
+	TokenStandard  string // token standard detected by ERC165 or method probing, e.g., ERC20, ERC721; blank if not a token
+	Methods        map[string]*abi.Method
+	Events         map[string]*abi.Event
+}
+
+// total supply of a token contract read at a block
+type TokenSupply struct {
+	Address      string
+	TotalSupply  float64
+	ExactSupply  *big.Int
+	BlockNumber  uint64
+	SnapshotTime int64 // Unix seconds when the supply is read
 }
 
 type Block struct {
@@ -60,13 +109,53 @@ type Transaction struct {
 	From        string
 	To          string
 	Input       []byte
-	Method      string // UNKNOWN indicates failure due to missing or bad contract ABI
+	Method      string // UNKNOWN indicates failure due to missing or bad contract ABI, blank if no call to contract
+	Standard    string // standard of the matched standard method, e.g., ERC20, Multicall3; blank if decoded by contract ABI
 	Params      []*NamedValue
 	GasPrice    uint64
 	Gas         uint64
 	Value       *big.Int
 	Nonce       uint64
 	BlockTime   int64
+	Calls       []*InnerCall     // inner calls of a batch transaction, e.g., multicall, or Gnosis Safe execTransaction
+	UserOps     []*UserOperation // ERC-4337 user operations bundled in a handleOps transaction of an EntryPoint
+}
+
+// inner call of a batch transaction, stored as a child record of the transaction
+type InnerCall struct {
+	TxnHash     string // hash of the parent transaction
+	Path        string // position of the call in nested batches, e.g., '1' for the second call, '1.0' for the first call in the second call
+	BlockNumber uint64
+	From        string // address that makes the call, i.e., the calling Safe if the batch contract is invoked by delegatecall
+	To          string // target contract of the call
+	Operation   string // CALL, or DELEGATECALL that executes code of the target in the context of the caller
+	Input       []byte
+	Method      string // UNKNOWN indicates failure due to missing or bad contract ABI
+	Standard    string // standard of the matched standard method, blank if decoded by contract ABI
+	Params      []*NamedValue
+	Value       *big.Int
+	BlockTime   int64
+}
+
+// ERC-4337 user operation executed by handleOps of an EntryPoint contract, stored as a child record of the bundle transaction
+type UserOperation struct {
+	TxnHash       string // hash of the bundle transaction
+	OpIndex       int    // position of the operation in the bundle
+	BlockNumber   uint64
+	EntryPoint    string   // address of the EntryPoint contract
+	Sender        string   // smart account that executes the callData
+	Nonce         *big.Int // 192-bit key and 64-bit sequence
+	Factory       string   // factory in initCode that deploys the account, blank if the account exists
+	Paymaster     string   // paymaster in paymasterAndData, blank if the sender pays for gas
+	CallData      []byte
+	Method        string // UNKNOWN indicates failure due to missing or bad account ABI, blank if no callData
+	Standard      string // standard of the matched standard method, blank if decoded by account ABI
+	Params        []*NamedValue
+	UserOpHash    string // from the UserOperationEvent, blank if the event is not found
+	Success       bool
+	ActualGasCost *big.Int
+	ActualGasUsed uint64
+	BlockTime     int64
 }
 
 type EventLog struct {
@@ -76,9 +165,12 @@ type EventLog struct {
 	TxnIndex    uint64
 	TxnHash     string
 	Address     string
+	Topics      []string // hex of indexed topics, starting with the event signature
 	Data        []byte
 	Event       string // UNKNOWN indicates failure due to missing or bad contract ABI
+	Standard    string // token standard of the matched standard event, blank if decoded by contract ABI
 	Params      []*NamedValue
+	Amount      float64 // decimals-adjusted amount of ERC20 Transfer, Approval, Mint or Burn events
 	BlockTime   int64
 }
 
@@ -113,6 +205,73 @@ func BigIntToFloat(i *big.Int) float64 {
 	return v
 }
 
+// return exact decimal string of a big integer, or "0" if it is nil
+func BigIntToString(i *big.Int) string {
+	if i == nil {
+		return "0"
+	}
+	return i.String()
+}
+
+// return exact decimal string of an integer param, e.g., *big.Int of uint256 or uint8 of uint8.
+// returns false if the param is not an integer
+func ParamToDecimal(v *NamedValue) (string, bool) {
+	if v.Kind != abi.KindUInt && v.Kind != abi.KindInt {
+		return "", false
+	}
+	if i, ok := v.Value.(*big.Int); ok {
+		if i == nil {
+			return "", false
+		}
+		return i.String(), true
+	}
+	ref := reflect.ValueOf(v.Value)
+	switch ref.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(ref.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(ref.Uint(), 10), true
+	}
+	return "", false
+}
+
+// return value of a param that is rendered as proper JSON, i.e.,
+// address and bytes as hex with prefix 0x, and components of tuple or array params converted recursively.
+func ParamJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case web3.Address:
+		return strings.ToLower(v.String())
+	case web3.Hash:
+		return v.String()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for k, c := range v {
+			result[k] = ParamJSONValue(c)
+		}
+		return result
+	}
+	ref := reflect.ValueOf(value)
+	switch ref.Kind() {
+	case reflect.Array, reflect.Slice:
+		if ref.Type().Elem().Kind() == reflect.Uint8 {
+			// fixed bytes, e.g., bytes32
+			b := make([]byte, ref.Len())
+			for i := 0; i < ref.Len(); i++ {
+				b[i] = uint8(ref.Index(i).Uint())
+			}
+			return "0x" + hex.EncodeToString(b)
+		}
+		result := make([]interface{}, ref.Len())
+		for i := 0; i < ref.Len(); i++ {
+			result[i] = ParamJSONValue(ref.Index(i).Interface())
+		}
+		return result
+	}
+	return value
+}
+
 func StringToBigInt(s string) *big.Int {
 	if bint, ok := new(big.Int).SetString(s, 10); ok {
 		return bint
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+// ERC165 Contract public interface based on OpenZeppelin Contracts v4.3.2 (utils/introspection/ERC165.sol)
+
+pragma solidity ^0.8.0;
+
+/**
+ * @dev Implementation of the {IERC165} interface as defined in
+ * https://eips.ethereum.org/EIPS/eip-165[EIP-165].
+ */
+abstract contract ERC165 {
+    /**
+     * @dev See {IERC165-supportsInterface}.
+     */
+    function supportsInterface(bytes4 interfaceId)
+        public
+        view
+        virtual
+        returns (bool);
+}
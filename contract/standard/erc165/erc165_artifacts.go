@@ -0,0 +1,35 @@
+package erc165
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiERC165 *abi.ABI
+
+// ERC165Abi returns the abi of the ERC165 contract
+func ERC165Abi() *abi.ABI {
+	return abiERC165
+}
+
+var binERC165 []byte
+
+func init() {
+	var err error
+	abiERC165, err = abi.NewABI(abiERC165Str)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse ERC165 abi: %v", err))
+	}
+	if len(binERC165Str) != 0 {
+		binERC165, err = hex.DecodeString(binERC165Str[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse ERC165 bin: %v", err))
+		}
+	}
+}
+
+var binERC165Str = ""
+
+var abiERC165Str = `[{"inputs":[{"internalType":"bytes4","name":"interfaceId","type":"bytes4"}],"name":"supportsInterface","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
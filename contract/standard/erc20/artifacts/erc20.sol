@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+// ERC20 Contract public interface based on OpenZeppelin Contracts v4.3.2 (token/ERC20/ERC20.sol)
+
+pragma solidity ^0.8.0;
+
+/**
+ * @dev Implementation of https://eips.ethereum.org/EIPS/eip-20[ERC20] Token Standard, including
+ * the Metadata extension.
+ */
+abstract contract ERC20 {
+    /**
+     * @dev See {IERC20Metadata-name}.
+     */
+    function name() public view virtual returns (string memory);
+
+    /**
+     * @dev See {IERC20Metadata-symbol}.
+     */
+    function symbol() public view virtual returns (string memory);
+
+    /**
+     * @dev See {IERC20Metadata-decimals}.
+     */
+    function decimals() public view virtual returns (uint8);
+
+    /**
+     * @dev See {IERC20-totalSupply}.
+     */
+    function totalSupply() public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC20-balanceOf}.
+     */
+    function balanceOf(address account) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC20-transfer}.
+     */
+    function transfer(address to, uint256 amount) public virtual returns (bool);
+
+    /**
+     * @dev See {IERC20-allowance}.
+     */
+    function allowance(address owner, address spender)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC20-approve}.
+     */
+    function approve(address spender, uint256 amount)
+        public
+        virtual
+        returns (bool);
+
+    /**
+     * @dev See {IERC20-transferFrom}.
+     */
+    function transferFrom(
+        address from,
+        address to,
+        uint256 amount
+    ) public virtual returns (bool);
+
+    // ERC20 events
+    /**
+     * @dev Emitted when `value` tokens are moved from one account (`from`) to
+     * another (`to`).
+     */
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    /**
+     * @dev Emitted when the allowance of a `spender` for an `owner` is set by
+     * a call to {approve}. `value` is the new allowance.
+     */
+    event Approval(
+        address indexed owner,
+        address indexed spender,
+        uint256 value
+    );
+}
+
+/**
+ * @dev Metadata of early ERC20 tokens, e.g., MKR and SAI, that return name and symbol as bytes32.
+ */
+abstract contract ERC20Bytes32 {
+    function name() public view virtual returns (bytes32);
+
+    function symbol() public view virtual returns (bytes32);
+}
@@ -0,0 +1,35 @@
+package erc20
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiERC20Bytes32 *abi.ABI
+
+// ERC20Bytes32Abi returns the abi of the ERC20Bytes32 contract
+func ERC20Bytes32Abi() *abi.ABI {
+	return abiERC20Bytes32
+}
+
+var binERC20Bytes32 []byte
+
+func init() {
+	var err error
+	abiERC20Bytes32, err = abi.NewABI(abiERC20Bytes32Str)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse ERC20Bytes32 abi: %v", err))
+	}
+	if len(binERC20Bytes32Str) != 0 {
+		binERC20Bytes32, err = hex.DecodeString(binERC20Bytes32Str[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse ERC20Bytes32 bin: %v", err))
+		}
+	}
+}
+
+var binERC20Bytes32Str = ""
+
+var abiERC20Bytes32Str = `[{"inputs":[],"name":"name","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"symbol","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"}]`
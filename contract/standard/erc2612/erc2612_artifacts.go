@@ -0,0 +1,35 @@
+package erc2612
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiERC2612 *abi.ABI
+
+// ERC2612Abi returns the abi of the ERC2612 contract
+func ERC2612Abi() *abi.ABI {
+	return abiERC2612
+}
+
+var binERC2612 []byte
+
+func init() {
+	var err error
+	abiERC2612, err = abi.NewABI(abiERC2612Str)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse ERC2612 abi: %v", err))
+	}
+	if len(binERC2612Str) != 0 {
+		binERC2612, err = hex.DecodeString(binERC2612Str[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse ERC2612 bin: %v", err))
+		}
+	}
+}
+
+var binERC2612Str = ""
+
+var abiERC2612Str = `[{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"nonces","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"},{"internalType":"uint8","name":"v","type":"uint8"},{"internalType":"bytes32","name":"r","type":"bytes32"},{"internalType":"bytes32","name":"s","type":"bytes32"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: GPL-3.0
+// ERC-4337 EntryPoint public interfaces based on account-abstraction v0.6.0 (IEntryPoint.sol) and v0.7.0 (IEntryPoint.sol)
+
+pragma solidity ^0.8.12;
+
+/**
+ * @dev User Operation struct of EntryPoint v0.6
+ */
+struct UserOperation {
+    address sender;
+    uint256 nonce;
+    bytes initCode;
+    bytes callData;
+    uint256 callGasLimit;
+    uint256 verificationGasLimit;
+    uint256 preVerificationGas;
+    uint256 maxFeePerGas;
+    uint256 maxPriorityFeePerGas;
+    bytes paymasterAndData;
+    bytes signature;
+}
+
+/**
+ * @dev User Operation struct of EntryPoint v0.7, which packs gas limits and fees into bytes32
+ */
+struct PackedUserOperation {
+    address sender;
+    uint256 nonce;
+    bytes initCode;
+    bytes callData;
+    bytes32 accountGasLimits;
+    uint256 preVerificationGas;
+    bytes32 gasFees;
+    bytes paymasterAndData;
+    bytes signature;
+}
+
+abstract contract EntryPointEvents {
+    /**
+     * @dev An event emitted after each successful request
+     */
+    event UserOperationEvent(
+        bytes32 indexed userOpHash,
+        address indexed sender,
+        address indexed paymaster,
+        uint256 nonce,
+        bool success,
+        uint256 actualGasCost,
+        uint256 actualGasUsed
+    );
+
+    /**
+     * @dev Account "sender" was deployed.
+     */
+    event AccountDeployed(bytes32 indexed userOpHash, address indexed sender, address factory, address paymaster);
+
+    /**
+     * @dev An event emitted if the UserOperation "callData" reverted with non-zero length
+     */
+    event UserOperationRevertReason(bytes32 indexed userOpHash, address indexed sender, uint256 nonce, bytes revertReason);
+
+    /**
+     * @dev Return the next nonce for this sender.
+     */
+    function getNonce(address sender, uint192 key) external view virtual returns (uint256 nonce);
+}
+
+abstract contract EntryPoint is EntryPointEvents {
+    /**
+     * @dev Execute a batch of UserOperations.
+     */
+    function handleOps(UserOperation[] calldata ops, address payable beneficiary) public virtual;
+}
+
+abstract contract EntryPointV7 is EntryPointEvents {
+    /**
+     * @dev Execute a batch of PackedUserOperations.
+     */
+    function handleOps(PackedUserOperation[] calldata ops, address payable beneficiary) public virtual;
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// ERC4626 Contract public interface based on OpenZeppelin Contracts v4.7.0 (interfaces/IERC4626.sol)
+
+pragma solidity ^0.8.0;
+
+/**
+ * @dev Implementation of the ERC4626 "Tokenized Vault Standard" as defined in
+ * https://eips.ethereum.org/EIPS/eip-4626[EIP-4626], not including the ERC20 methods of the vault shares.
+ */
+abstract contract ERC4626 {
+    /**
+     * @dev See {IERC4626-asset}.
+     */
+    function asset() public view virtual returns (address);
+
+    /**
+     * @dev See {IERC4626-totalAssets}.
+     */
+    function totalAssets() public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-convertToShares}.
+     */
+    function convertToShares(uint256 assets)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-convertToAssets}.
+     */
+    function convertToAssets(uint256 shares)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-maxDeposit}.
+     */
+    function maxDeposit(address receiver) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-previewDeposit}.
+     */
+    function previewDeposit(uint256 assets)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-deposit}.
+     */
+    function deposit(uint256 assets, address receiver)
+        public
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-maxMint}.
+     */
+    function maxMint(address receiver) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-previewMint}.
+     */
+    function previewMint(uint256 shares) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-mint}.
+     */
+    function mint(uint256 shares, address receiver)
+        public
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-maxWithdraw}.
+     */
+    function maxWithdraw(address owner) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-previewWithdraw}.
+     */
+    function previewWithdraw(uint256 assets)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-withdraw}.
+     */
+    function withdraw(
+        uint256 assets,
+        address receiver,
+        address owner
+    ) public virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-maxRedeem}.
+     */
+    function maxRedeem(address owner) public view virtual returns (uint256);
+
+    /**
+     * @dev See {IERC4626-previewRedeem}.
+     */
+    function previewRedeem(uint256 shares)
+        public
+        view
+        virtual
+        returns (uint256);
+
+    /**
+     * @dev See {IERC4626-redeem}.
+     */
+    function redeem(
+        uint256 shares,
+        address receiver,
+        address owner
+    ) public virtual returns (uint256);
+
+    // ERC4626 events
+    /**
+     * @dev Emitted when `sender` deposits `assets` to the vault and `owner` receives `shares`.
+     */
+    event Deposit(
+        address indexed sender,
+        address indexed owner,
+        uint256 assets,
+        uint256 shares
+    );
+
+    /**
+     * @dev Emitted when `sender` withdraws `assets` of `owner` to `receiver` by burning `shares`.
+     */
+    event Withdraw(
+        address indexed sender,
+        address indexed receiver,
+        address indexed owner,
+        uint256 assets,
+        uint256 shares
+    );
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: MIT
+// Batch call public interfaces based on Multicall3 (0xca11bde05977b3631167028862be2a173976ca11)
+// and Uniswap v3-periphery IMulticall and IMulticallExtended
+
+pragma solidity ^0.8.0;
+
+/**
+ * @dev Aggregate results from multiple function calls of any target contracts.
+ */
+abstract contract Multicall3 {
+    struct Call {
+        address target;
+        bytes callData;
+    }
+
+    struct Call3 {
+        address target;
+        bool allowFailure;
+        bytes callData;
+    }
+
+    struct Call3Value {
+        address target;
+        bool allowFailure;
+        uint256 value;
+        bytes callData;
+    }
+
+    struct Result {
+        bool success;
+        bytes returnData;
+    }
+
+    /**
+     * @dev Backwards-compatible call aggregation with Multicall.
+     */
+    function aggregate(Call[] calldata calls)
+        public
+        payable
+        virtual
+        returns (uint256 blockNumber, bytes[] memory returnData);
+
+    /**
+     * @dev Backwards-compatible with Multicall2, aggregate calls without requiring success.
+     */
+    function tryAggregate(bool requireSuccess, Call[] calldata calls)
+        public
+        payable
+        virtual
+        returns (Result[] memory returnData);
+
+    /**
+     * @dev Backwards-compatible with Multicall2, aggregate calls and allow failures using tryAggregate.
+     */
+    function tryBlockAndAggregate(bool requireSuccess, Call[] calldata calls)
+        public
+        payable
+        virtual
+        returns (
+            uint256 blockNumber,
+            bytes32 blockHash,
+            Result[] memory returnData
+        );
+
+    /**
+     * @dev Backwards-compatible with Multicall2, aggregate calls and require success.
+     */
+    function blockAndAggregate(Call[] calldata calls)
+        public
+        payable
+        virtual
+        returns (
+            uint256 blockNumber,
+            bytes32 blockHash,
+            Result[] memory returnData
+        );
+
+    /**
+     * @dev Aggregate calls, ensuring each returns success if required.
+     */
+    function aggregate3(Call3[] calldata calls)
+        public
+        payable
+        virtual
+        returns (Result[] memory returnData);
+
+    /**
+     * @dev Aggregate calls with a msg value, reverting if msg.value is less than the sum of the call values.
+     */
+    function aggregate3Value(Call3Value[] calldata calls)
+        public
+        payable
+        virtual
+        returns (Result[] memory returnData);
+}
+
+/**
+ * @dev Call multiple methods of the same contract in a single call, e.g., Uniswap router and position manager.
+ */
+abstract contract Multicall {
+    /**
+     * @dev See {IMulticall-multicall}.
+     */
+    function multicall(bytes[] calldata data)
+        public
+        payable
+        virtual
+        returns (bytes[] memory results);
+
+    /**
+     * @dev See {IMulticallExtended-multicall}, call multiple methods and check the deadline.
+     */
+    function multicall(uint256 deadline, bytes[] calldata data)
+        public
+        payable
+        virtual
+        returns (bytes[] memory results);
+
+    /**
+     * @dev See {IMulticallExtended-multicall}, call multiple methods and check the previous block hash.
+     */
+    function multicall(bytes32 previousBlockhash, bytes[] calldata data)
+        public
+        payable
+        virtual
+        returns (bytes[] memory results);
+}
@@ -0,0 +1,35 @@
+package multicall
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiMulticall *abi.ABI
+
+// MulticallAbi returns the abi of the Multicall contract
+func MulticallAbi() *abi.ABI {
+	return abiMulticall
+}
+
+var binMulticall []byte
+
+func init() {
+	var err error
+	abiMulticall, err = abi.NewABI(abiMulticallStr)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse Multicall abi: %v", err))
+	}
+	if len(binMulticallStr) != 0 {
+		binMulticall, err = hex.DecodeString(binMulticallStr[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse Multicall bin: %v", err))
+		}
+	}
+}
+
+var binMulticallStr = ""
+
+var abiMulticallStr = `[{"inputs":[{"internalType":"bytes[]","name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"internalType":"bytes[]","name":"results","type":"bytes[]"}],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"uint256","name":"deadline","type":"uint256"},{"internalType":"bytes[]","name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"internalType":"bytes[]","name":"results","type":"bytes[]"}],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"bytes32","name":"previousBlockhash","type":"bytes32"},{"internalType":"bytes[]","name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"internalType":"bytes[]","name":"results","type":"bytes[]"}],"stateMutability":"payable","type":"function"}]`
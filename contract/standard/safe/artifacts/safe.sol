@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: LGPL-3.0-only
+// Gnosis Safe public interfaces based on safe-contracts v1.3.0 (GnosisSafe.sol and libraries/MultiSendCallOnly.sol)
+
+pragma solidity ^0.8.0;
+
+abstract contract Enum {
+    enum Operation {
+        Call,
+        DelegateCall
+    }
+}
+
+/**
+ * @dev Multisignature wallet with support for confirmations using signed messages based on ERC191.
+ */
+abstract contract GnosisSafe {
+    /**
+     * @dev Executes a `operation` {0: Call, 1: DelegateCall}} transaction to `to` with `value` (Native Currency)
+     * and pays `gasPrice` * `gasLimit` in `gasToken` token to `refundReceiver`.
+     */
+    function execTransaction(
+        address to,
+        uint256 value,
+        bytes calldata data,
+        Enum.Operation operation,
+        uint256 safeTxGas,
+        uint256 baseGas,
+        uint256 gasPrice,
+        address gasToken,
+        address payable refundReceiver,
+        bytes memory signatures
+    ) public payable virtual returns (bool success);
+
+    function nonce() public view virtual returns (uint256);
+
+    function getThreshold() public view virtual returns (uint256);
+
+    function getOwners() public view virtual returns (address[] memory);
+
+    // GnosisSafe events
+    event ExecutionFailure(bytes32 txHash, uint256 payment);
+
+    event ExecutionSuccess(bytes32 txHash, uint256 payment);
+}
+
+/**
+ * @dev Batch multiple transactions into one.
+ */
+abstract contract MultiSend {
+    /**
+     * @dev Sends multiple transactions and reverts all if one fails.
+     * @param transactions Encoded transactions. Each transaction is encoded as a packed bytes of
+     *                     operation as a uint8 with 0 for a call or 1 for a delegatecall (=> 1 byte),
+     *                     to as a address (=> 20 bytes),
+     *                     value as a uint256 (=> 32 bytes),
+     *                     data length as a uint256 (=> 32 bytes),
+     *                     data as bytes.
+     */
+    function multiSend(bytes memory transactions) public payable virtual;
+}
@@ -0,0 +1,35 @@
+package safe
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiGnosisSafe *abi.ABI
+
+// GnosisSafeAbi returns the abi of the GnosisSafe contract
+func GnosisSafeAbi() *abi.ABI {
+	return abiGnosisSafe
+}
+
+var binGnosisSafe []byte
+
+func init() {
+	var err error
+	abiGnosisSafe, err = abi.NewABI(abiGnosisSafeStr)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse GnosisSafe abi: %v", err))
+	}
+	if len(binGnosisSafeStr) != 0 {
+		binGnosisSafe, err = hex.DecodeString(binGnosisSafeStr[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse GnosisSafe bin: %v", err))
+		}
+	}
+}
+
+var binGnosisSafeStr = ""
+
+var abiGnosisSafeStr = `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"txHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"payment","type":"uint256"}],"name":"ExecutionFailure","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"txHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"payment","type":"uint256"}],"name":"ExecutionSuccess","type":"event"},{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"enum Enum.Operation","name":"operation","type":"uint8"},{"internalType":"uint256","name":"safeTxGas","type":"uint256"},{"internalType":"uint256","name":"baseGas","type":"uint256"},{"internalType":"uint256","name":"gasPrice","type":"uint256"},{"internalType":"address","name":"gasToken","type":"address"},{"internalType":"address payable","name":"refundReceiver","type":"address"},{"internalType":"bytes","name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"internalType":"bool","name":"success","type":"bool"}],"stateMutability":"payable","type":"function"},{"inputs":[],"name":"getOwners","outputs":[{"internalType":"address[]","name":"","type":"address[]"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"getThreshold","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"nonce","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
@@ -0,0 +1,35 @@
+package safe
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiMultiSend *abi.ABI
+
+// MultiSendAbi returns the abi of the MultiSend contract
+func MultiSendAbi() *abi.ABI {
+	return abiMultiSend
+}
+
+var binMultiSend []byte
+
+func init() {
+	var err error
+	abiMultiSend, err = abi.NewABI(abiMultiSendStr)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse MultiSend abi: %v", err))
+	}
+	if len(binMultiSendStr) != 0 {
+		binMultiSend, err = hex.DecodeString(binMultiSendStr[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse MultiSend bin: %v", err))
+		}
+	}
+}
+
+var binMultiSendStr = ""
+
+var abiMultiSendStr = `[{"inputs":[{"internalType":"bytes","name":"transactions","type":"bytes"}],"name":"multiSend","outputs":[],"stateMutability":"payable","type":"function"}]`
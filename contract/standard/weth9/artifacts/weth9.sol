@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: GPL-3.0
+// WETH9 Contract public interface based on Wrapped Ether (0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2),
+// not including the ERC20 methods and events.
+
+pragma solidity ^0.8.0;
+
+abstract contract WETH9 {
+    /**
+     * @dev Wrap the ETH sent with the transaction.
+     */
+    function deposit() public payable virtual;
+
+    /**
+     * @dev Unwrap `wad` WETH and send the ETH to the caller.
+     */
+    function withdraw(uint256 wad) public virtual;
+
+    // WETH9 events
+    /**
+     * @dev Emitted when `wad` ETH is wrapped for `dst`.
+     */
+    event Deposit(address indexed dst, uint256 wad);
+
+    /**
+     * @dev Emitted when `wad` WETH is unwrapped by `src`.
+     */
+    event Withdrawal(address indexed src, uint256 wad);
+}
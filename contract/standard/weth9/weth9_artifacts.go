@@ -0,0 +1,35 @@
+package weth9
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/umbracle/ethgo/abi"
+)
+
+var abiWETH9 *abi.ABI
+
+// WETH9Abi returns the abi of the WETH9 contract
+func WETH9Abi() *abi.ABI {
+	return abiWETH9
+}
+
+var binWETH9 []byte
+
+func init() {
+	var err error
+	abiWETH9, err = abi.NewABI(abiWETH9Str)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse WETH9 abi: %v", err))
+	}
+	if len(binWETH9Str) != 0 {
+		binWETH9, err = hex.DecodeString(binWETH9Str[2:])
+		if err != nil {
+			panic(fmt.Errorf("cannot parse WETH9 bin: %v", err))
+		}
+	}
+}
+
+var binWETH9Str = ""
+
+var abiWETH9Str = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"dst","type":"address"},{"indexed":false,"internalType":"uint256","name":"wad","type":"uint256"}],"name":"Deposit","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"src","type":"address"},{"indexed":false,"internalType":"uint256","name":"wad","type":"uint256"}],"name":"Withdrawal","type":"event"},{"inputs":[],"name":"deposit","outputs":[],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"uint256","name":"wad","type":"uint256"}],"name":"withdraw","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nats-io/nats.go"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+)
+
+// placeholders of subject templates, e.g., eth.logs.<address>.<event>
+const (
+	subjectAddress  = "<address>"  // contract address, i.e., to-address of a transaction, or address of an event log
+	subjectEvent    = "<event>"    // event name of an event log
+	subjectMethod   = "<method>"   // method name of a transaction
+	subjectStandard = "<standard>" // token standard of the contract or event as stored, e.g., ERC20
+)
+
+// placeholder value for a blank field, so subjects do not contain empty tokens
+const unknownToken = "unknown"
+
+// publish decoded transactions and event logs to NATS subjects keyed by contract address.
+// With JetStream, WriteBlocks returns only after all messages are acked by a stream, and the block range is retried
+// if it fails, so messages are delivered at least once before progress of the block interval is saved.
+// Each message carries a message ID for the stream to discard duplicates of retried ranges.
+// With core NATS, messages are only flushed to the server, which gives no delivery guarantee,
+// i.e., messages are lost if no subscriber is connected or the server restarts.
+type NATSSink struct {
+	conn       *nats.Conn
+	js         nats.JetStreamContext // nil to publish using core NATS
+	txSubject  string                // subject template of transactions, blank to not publish transactions
+	logSubject string                // subject template of event logs, blank to not publish event logs
+	timeout    time.Duration         // max wait for confirmation of published messages
+}
+
+// connect to the NATS server at url, and publish to subjects of the templates.
+// if jetStream is true, messages are published to JetStream, which must have a stream for the subjects,
+// otherwise messages are published using core NATS without delivery guarantee.
+func NewNATSSink(url, txSubject, logSubject string, jetStream bool) (*NATSSink, error) {
+	conn, err := nats.Connect(url, nats.Name("eth-track"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to connect to NATS %s", url)
+	}
+	s := &NATSSink{
+		conn:       conn,
+		txSubject:  txSubject,
+		logSubject: logSubject,
+		timeout:    30 * time.Second,
+	}
+	if !jetStream {
+		glog.Warningf("Publish to core NATS %s without delivery guarantee", url)
+		return s, nil
+	}
+	if s.js, err = conn.JetStream(); err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "Failed to create JetStream context of NATS %s", url)
+	}
+	return s, nil
+}
+
+// publish transactions and event logs of blocks in order of block number, and wait for confirmation from the server
+func (s *NATSSink) WriteBlocks(blocks map[string]*common.Block) error {
+	var futures []nats.PubAckFuture
+	count := 0
+	for _, b := range sortedBlocks(blocks) {
+		if len(s.txSubject) > 0 {
+			for _, t := range sortedTransactions(b) {
+				subject := renderSubject(s.txSubject, t.To, "", t.Method, t.Standard)
+				f, err := s.publish(subject, t.Hash, toJSONTransaction(t))
+				if err != nil {
+					return errors.Wrapf(err, "Failed to publish transaction %s", t.Hash)
+				}
+				futures = append(futures, f...)
+				count++
+			}
+		}
+		if len(s.logSubject) > 0 {
+			for _, evt := range sortedLogs(b) {
+				subject := renderSubject(s.logSubject, evt.Address, evt.Event, "", evt.Standard)
+				id := fmt.Sprintf("%s-%d", evt.TxnHash, evt.LogIndex)
+				f, err := s.publish(subject, id, toJSONLog(evt))
+				if err != nil {
+					return errors.Wrapf(err, "Failed to publish event log %d of block %d", evt.LogIndex, evt.BlockNumber)
+				}
+				futures = append(futures, f...)
+				count++
+			}
+		}
+	}
+	if err := s.confirm(futures); err != nil {
+		return err
+	}
+	if glog.V(1) {
+		glog.Infof("Published %d messages of %d blocks to NATS", count, len(blocks))
+	}
+	return nil
+}
+
+// publish JSON of a record to the subject, and return the future of JetStream ack
+func (s *NATSSink) publish(subject, id string, record interface{}) ([]nats.PubAckFuture, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if s.js == nil {
+		return nil, s.conn.Publish(subject, data)
+	}
+	f, err := s.js.PublishAsync(subject, data, nats.MsgId(id))
+	if err != nil {
+		return nil, err
+	}
+	return []nats.PubAckFuture{f}, nil
+}
+
+// wait for acks of JetStream, or flush core NATS messages to the server
+func (s *NATSSink) confirm(futures []nats.PubAckFuture) error {
+	if s.js == nil {
+		return errors.Wrap(s.conn.FlushTimeout(s.timeout), "Failed to flush NATS messages")
+	}
+	deadline := time.After(s.timeout)
+	for _, f := range futures {
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			return errors.Wrapf(err, "Failed to publish NATS message to %s", f.Msg().Subject)
+		case <-deadline:
+			return errors.Errorf("Timeout waiting for JetStream acks of %d messages", len(futures))
+		}
+	}
+	return nil
+}
+
+func (s *NATSSink) Close() error {
+	if err := s.conn.Drain(); err != nil {
+		s.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// replace placeholders of a subject template by tokens of the record
+func renderSubject(template, address, event, method, standard string) string {
+	r := strings.NewReplacer(
+		subjectAddress, subjectToken(strings.ToLower(address)),
+		subjectEvent, subjectToken(event),
+		subjectMethod, subjectToken(method),
+		subjectStandard, subjectToken(standard),
+	)
+	return r.Replace(template)
+}
+
+// return a valid token of NATS subject, which must not be blank or contain separators or wildcards
+func subjectToken(value string) string {
+	if len(value) == 0 {
+		return unknownToken
+	}
+	return strings.Map(func(c rune) rune {
+		switch c {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return c
+	}, value)
+}
@@ -0,0 +1,62 @@
+package export
+
+// Run all unit test: `go test -v`
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSSink(t *testing.T) {
+	// embedded NATS server with JetStream
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	require.NoError(t, err, "create NATS server should not throw error")
+	go ns.Start()
+	defer ns.Shutdown()
+	require.True(t, ns.ReadyForConnections(5*time.Second), "NATS server should be ready")
+
+	nc, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err, "connect to NATS should not throw error")
+	defer nc.Close()
+	js, err := nc.JetStream()
+	require.NoError(t, err, "create JetStream context should not throw error")
+	_, err = js.AddStream(&nats.StreamConfig{Name: "ETH", Subjects: []string{"eth.>"}})
+	require.NoError(t, err, "add stream should not throw error")
+
+	sink, err := NewNATSSink(ns.ClientURL(), "eth.txs.<address>.<method>", "eth.logs.<address>.<event>", true)
+	require.NoError(t, err, "create NATS sink should not throw error")
+	require.NoError(t, sink.WriteBlocks(sampleBlocks()), "publish blocks should not throw error")
+
+	sub, err := js.SubscribeSync("eth.logs.0xdac17f958d2ee523a2206206994597c13d831ec7.Transfer", nats.DeliverAll())
+	require.NoError(t, err, "subscribe to event logs should not throw error")
+	msg, err := sub.NextMsg(5 * time.Second)
+	require.NoError(t, err, "event log should be published to subject of contract address and event")
+	var evt map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg.Data, &evt), "message should be JSON")
+	assert.Equal(t, "Transfer", evt["event"], "event of published log")
+	params := evt["params"].(map[string]interface{})
+	assert.Equal(t, "0xa9059cbb", params["data"], "params should be a JSON object")
+	sub, err = js.SubscribeSync("eth.txs.unknown.transfer", nats.DeliverAll())
+	require.NoError(t, err, "subscribe to transactions should not throw error")
+	_, err = sub.NextMsg(5 * time.Second)
+	assert.NoError(t, err, "transaction without to-address should be published to subject of unknown address")
+
+	// retry of the same block range is discarded as duplicates by the stream
+	require.NoError(t, sink.WriteBlocks(sampleBlocks()), "publish blocks again should not throw error")
+	info, err := js.StreamInfo("ETH")
+	require.NoError(t, err, "stream info should not throw error")
+	assert.Equal(t, uint64(2), info.State.Msgs, "duplicate messages should be discarded")
+	assert.NoError(t, sink.Close(), "close sink should not throw error")
+}
+
+func TestRenderSubject(t *testing.T) {
+	subject := renderSubject("eth.logs.<standard>.<address>.<event>", "0xDAC17F958D2EE523A2206206994597C13D831EC7", "", "", "ERC20")
+	assert.Equal(t, "eth.logs.ERC20.0xdac17f958d2ee523a2206206994597c13d831ec7.unknown", subject, "address should be lowercase and blank event unknown")
+	assert.Equal(t, "a_b_", subjectToken("a.b*"), "separators and wildcards should be replaced")
+}
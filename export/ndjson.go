@@ -0,0 +1,287 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+)
+
+type jsonBlock struct {
+	Type         string `json:"type"`
+	Hash         string `json:"hash"`
+	Number       uint64 `json:"number"`
+	ParentHash   string `json:"parent_hash"`
+	Miner        string `json:"miner"`
+	Difficulty   string `json:"difficulty"`
+	GasLimit     uint64 `json:"gas_limit"`
+	GasUsed      uint64 `json:"gas_used"`
+	BlockTime    string `json:"block_time"`
+	Transactions int    `json:"transactions"`
+	Logs         int    `json:"logs"`
+}
+
+type jsonTransaction struct {
+	Type        string                 `json:"type"`
+	Hash        string                 `json:"hash"`
+	BlockNumber uint64                 `json:"block_number"`
+	TxnIndex    uint64                 `json:"txn_index"`
+	Status      bool                   `json:"status"`
+	From        string                 `json:"from"`
+	To          string                 `json:"to"`
+	Method      string                 `json:"method,omitempty"`
+	Standard    string                 `json:"standard,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	GasPrice    uint64                 `json:"gas_price"`
+	Gas         uint64                 `json:"gas"`
+	Value       string                 `json:"value"`
+	Nonce       uint64                 `json:"nonce"`
+	BlockTime   string                 `json:"block_time"`
+}
+
+type jsonLog struct {
+	Type        string                 `json:"type"`
+	BlockNumber uint64                 `json:"block_number"`
+	LogIndex    uint64                 `json:"log_index"`
+	Removed     bool                   `json:"removed"`
+	TxnIndex    uint64                 `json:"txn_index"`
+	TxnHash     string                 `json:"txn_hash"`
+	Address     string                 `json:"address"`
+	Topics      []string               `json:"topics"`
+	Event       string                 `json:"event,omitempty"`
+	Standard    string                 `json:"standard,omitempty"`
+	Amount      float64                `json:"amount,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	BlockTime   string                 `json:"block_time"`
+}
+
+// export each decoded block, transaction and event log as a line of JSON to stdout, a file or a named pipe.
+// a regular file is rotated when its size exceeds the max size.
+type NDJSONSink struct {
+	sync.Mutex
+	path     string // file path, or - for stdout
+	maxBytes int64  // max size of a file before rotation, 0 for no rotation
+	out      io.WriteCloser
+	size     int64 // bytes written to the current file
+}
+
+// create a sink that writes to the file path, or stdout if path is -.
+// if maxBytes > 0, a regular file is renamed with a timestamp suffix when its size exceeds maxBytes, and a new file is started.
+func NewNDJSONSink(path string, maxBytes int64) (*NDJSONSink, error) {
+	s := &NDJSONSink{path: path, maxBytes: maxBytes}
+	if path == "-" {
+		s.out = os.Stdout
+		s.maxBytes = 0
+		return s, nil
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		// cannot rotate a named pipe
+		s.maxBytes = 0
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open the file for append. it blocks until a reader opens the pipe if the path is a named pipe.
+func (s *NDJSONSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open ndjson file %s", s.path)
+	}
+	s.out = f
+	s.size = 0
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		s.size = info.Size()
+	}
+	return nil
+}
+
+// rename the current file with a timestamp suffix, and open a new file
+func (s *NDJSONSink) rotate() error {
+	if err := s.out.Close(); err != nil {
+		return err
+	}
+	archive := fmt.Sprintf("%s.%s", s.path, time.Now().UTC().Format("20060102T150405.000"))
+	if err := os.Rename(s.path, archive); err != nil {
+		return errors.Wrapf(err, "Failed to rotate ndjson file %s", s.path)
+	}
+	glog.Infof("Rotated ndjson file %s to %s", s.path, archive)
+	return s.open()
+}
+
+// write blocks in order of block number, each followed by its transactions and event logs
+func (s *NDJSONSink) WriteBlocks(blocks map[string]*common.Block) error {
+	data, err := composeNDJSON(blocks)
+	if err != nil {
+		return err
+	}
+
+	// multiple workers may write concurrently
+	s.Lock()
+	defer s.Unlock()
+	if s.maxBytes > 0 && s.size > 0 && s.size+int64(len(data)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.out.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+func (s *NDJSONSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.out == os.Stdout {
+		return nil
+	}
+	return s.out.Close()
+}
+
+// compose lines of JSON for blocks, transactions and event logs
+func composeNDJSON(blocks map[string]*common.Block) ([]byte, error) {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	for _, b := range sortedBlocks(blocks) {
+		if err := enc.Encode(toJSONBlock(b)); err != nil {
+			return nil, err
+		}
+		for _, t := range sortedTransactions(b) {
+			if err := enc.Encode(toJSONTransaction(t)); err != nil {
+				return nil, errors.Wrapf(err, "Failed to encode transaction %s", t.Hash)
+			}
+		}
+		for _, evt := range sortedLogs(b) {
+			if err := enc.Encode(toJSONLog(evt)); err != nil {
+				return nil, errors.Wrapf(err, "Failed to encode event log %d of block %d", evt.LogIndex, evt.BlockNumber)
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// return blocks sorted by block number
+func sortedBlocks(blocks map[string]*common.Block) []*common.Block {
+	var sorted []*common.Block
+	for _, b := range blocks {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Number < sorted[j].Number
+	})
+	return sorted
+}
+
+// return transactions of a block sorted by transaction index
+func sortedTransactions(b *common.Block) []*common.Transaction {
+	var txns []*common.Transaction
+	for _, t := range b.Transactions {
+		txns = append(txns, t)
+	}
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].TxnIndex < txns[j].TxnIndex
+	})
+	return txns
+}
+
+// return event logs of a block sorted by log index
+func sortedLogs(b *common.Block) []*common.EventLog {
+	var logs []*common.EventLog
+	for _, evt := range b.Logs {
+		logs = append(logs, evt)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+	return logs
+}
+
+// format Unix seconds as UTC time in RFC 3339
+func formatBlockTime(t int64) string {
+	return common.SecondsToDateTime(t).Format(time.RFC3339)
+}
+
+func toJSONBlock(b *common.Block) *jsonBlock {
+	return &jsonBlock{
+		Type:         "block",
+		Hash:         b.Hash,
+		Number:       b.Number,
+		ParentHash:   b.ParentHash.String(),
+		Miner:        b.Miner,
+		Difficulty:   common.BigIntToString(b.Difficulty),
+		GasLimit:     b.GasLimit,
+		GasUsed:      b.GasUsed,
+		BlockTime:    formatBlockTime(b.BlockTime),
+		Transactions: len(b.Transactions),
+		Logs:         len(b.Logs),
+	}
+}
+
+func toJSONTransaction(t *common.Transaction) *jsonTransaction {
+	return &jsonTransaction{
+		Type:        "transaction",
+		Hash:        t.Hash,
+		BlockNumber: t.BlockNumber,
+		TxnIndex:    t.TxnIndex,
+		Status:      t.Status,
+		From:        t.From,
+		To:          t.To,
+		Method:      t.Method,
+		Standard:    t.Standard,
+		Params:      paramsObject(t.Params),
+		GasPrice:    t.GasPrice,
+		Gas:         t.Gas,
+		Value:       common.BigIntToString(t.Value),
+		Nonce:       t.Nonce,
+		BlockTime:   formatBlockTime(t.BlockTime),
+	}
+}
+
+func toJSONLog(evt *common.EventLog) *jsonLog {
+	topics := evt.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return &jsonLog{
+		Type:        "log",
+		BlockNumber: evt.BlockNumber,
+		LogIndex:    evt.LogIndex,
+		Removed:     evt.Removed,
+		TxnIndex:    evt.TxnIndex,
+		TxnHash:     evt.TxnHash,
+		Address:     evt.Address,
+		Topics:      topics,
+		Event:       evt.Event,
+		Standard:    evt.Standard,
+		Amount:      evt.Amount,
+		Params:      paramsObject(evt.Params),
+		BlockTime:   formatBlockTime(evt.BlockTime),
+	}
+}
+
+// render params as a JSON object of param names, and name unnamed params by position, e.g., arg0.
+// integers are rendered as exact JSON numbers.
+func paramsObject(params []*common.NamedValue) map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{})
+	for i, p := range params {
+		name := p.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		result[name] = common.ParamJSONValue(p.Value)
+	}
+	return result
+}
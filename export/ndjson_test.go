@@ -0,0 +1,48 @@
+package export
+
+// Run all unit test: `go test -v`
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.ndjson")
+	sink, err := NewNDJSONSink(path, 600)
+	require.NoError(t, err, "create ndjson sink should not throw error")
+	require.NoError(t, sink.WriteBlocks(sampleBlocks()), "write blocks should not throw error")
+
+	f, err := os.Open(path)
+	require.NoError(t, err, "open ndjson file should not throw error")
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var v map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &v), "each line should be a JSON object")
+		lines = append(lines, v)
+	}
+	f.Close()
+	require.Equal(t, 3, len(lines), "should write a block, a transaction and a log")
+	assert.Equal(t, "block", lines[0]["type"], "block should be written first")
+	assert.Equal(t, "transaction", lines[1]["type"], "transaction should follow its block")
+	params, ok := lines[1]["params"].(map[string]interface{})
+	require.True(t, ok, "params should be a JSON object")
+	assert.Equal(t, "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", params["to"], "address param as hex")
+	assert.Equal(t, 2500.0, params["value"], "uint256 param as JSON number")
+	logParams := lines[2]["params"].(map[string]interface{})
+	assert.Equal(t, "0xa9059cbb", logParams["data"], "fixed bytes param as hex")
+
+	// the next batch exceeds max size, so the file is rotated
+	require.NoError(t, sink.WriteBlocks(sampleBlocks()), "write blocks again should not throw error")
+	require.NoError(t, sink.Close(), "close sink should not throw error")
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err, "list ndjson files should not throw error")
+	assert.Equal(t, 2, len(files), "ndjson file should be rotated")
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+)
+
+// return text of a param value, and its numeric value if it is an integer or bool.
+// address and bytes are rendered as hex, and tuple or array params as JSON.
+func paramText(p *common.NamedValue) (string, *float64) {
+	if d, ok := common.ParamToDecimal(p); ok {
+		f, _ := new(big.Float).SetString(d)
+		num, _ := f.Float64()
+		return d, &num
+	}
+	switch v := p.Value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		num := 0.0
+		if v {
+			num = 1
+		}
+		return strconv.FormatBool(v), &num
+	case nil:
+		return "", nil
+	}
+	value := common.ParamJSONValue(p.Value)
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		glog.Warningf("Failed to serialize param %s %T: %v", p.Name, p.Value, err)
+		return "", nil
+	}
+	return string(data), nil
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type parquetBlock struct {
+	Hash            string  `parquet:"name=hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Number          int64   `parquet:"name=number, type=INT64"`
+	ParentHash      string  `parquet:"name=parent_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Miner           string  `parquet:"name=miner, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Difficulty      float64 `parquet:"name=difficulty, type=DOUBLE"`
+	ExactDifficulty string  `parquet:"name=exact_difficulty, type=BYTE_ARRAY, convertedtype=UTF8"`
+	GasLimit        int64   `parquet:"name=gas_limit, type=INT64"`
+	GasUsed         int64   `parquet:"name=gas_used, type=INT64"`
+	BlockTime       int64   `parquet:"name=block_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+type parquetTransaction struct {
+	Hash        string         `parquet:"name=hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BlockNumber int64          `parquet:"name=block_number, type=INT64"`
+	TxnIndex    int64          `parquet:"name=txn_index, type=INT64"`
+	Status      bool           `parquet:"name=status, type=BOOLEAN"`
+	From        string         `parquet:"name=from, type=BYTE_ARRAY, convertedtype=UTF8"`
+	To          string         `parquet:"name=to, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Method      string         `parquet:"name=method, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Standard    string         `parquet:"name=standard, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Params      []parquetParam `parquet:"name=params, repetitiontype=REPEATED"`
+	GasPrice    int64          `parquet:"name=gas_price, type=INT64"`
+	Gas         int64          `parquet:"name=gas, type=INT64"`
+	Value       float64        `parquet:"name=value, type=DOUBLE"`
+	ExactValue  string         `parquet:"name=exact_value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Nonce       int64          `parquet:"name=nonce, type=INT64"`
+	BlockTime   int64          `parquet:"name=block_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+type parquetLog struct {
+	BlockNumber int64          `parquet:"name=block_number, type=INT64"`
+	LogIndex    int64          `parquet:"name=log_index, type=INT64"`
+	Removed     bool           `parquet:"name=removed, type=BOOLEAN"`
+	TxnIndex    int64          `parquet:"name=txn_index, type=INT64"`
+	TxnHash     string         `parquet:"name=txn_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Address     string         `parquet:"name=address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Topics      []string       `parquet:"name=topics, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REPEATED"`
+	Event       string         `parquet:"name=event, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Standard    string         `parquet:"name=standard, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Amount      float64        `parquet:"name=amount, type=DOUBLE"`
+	Params      []parquetParam `parquet:"name=params, repetitiontype=REPEATED"`
+	BlockTime   int64          `parquet:"name=block_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+// decoded param stored as a repeated group of its transaction or event log
+type parquetParam struct {
+	Name  string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Kind  string   `parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Value string   `parquet:"name=value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Num   *float64 `parquet:"name=num, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Exact *string  `parquet:"name=exact, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+// destination of exported files, e.g., redshift.ObjectStore of a local folder or s3 bucket
+type FileStore interface {
+	WriteFile(name string, content []byte) error
+}
+
+// export decoded blocks, transactions and event logs as Parquet files,
+// partitioned Hive-style by chain and UTC date, e.g., logs/chain=ethereum/date=2022-01-01/13916166-13916205.parquet
+type ParquetSink struct {
+	store  FileStore
+	prefix string // folder of exported files in the store, blank for the root folder
+	chain  string // name of the blockchain, e.g., ethereum
+}
+
+func NewParquetSink(store FileStore, prefix, chain string) *ParquetSink {
+	return &ParquetSink{store: store, prefix: prefix, chain: chain}
+}
+
+// write blocks as Parquet files of each table and date.
+// files are named by the block range, so a reprocessed range overwrites the previous files.
+func (s *ParquetSink) WriteBlocks(blocks map[string]*common.Block) error {
+	for date, part := range partitionByDate(blocks) {
+		var blockRows, txRows, logRows []interface{}
+		for _, b := range part {
+			blockRows = append(blockRows, toParquetBlock(b))
+			for _, t := range b.Transactions {
+				txRows = append(txRows, toParquetTransaction(t))
+			}
+			for _, evt := range b.Logs {
+				logRows = append(logRows, toParquetLog(evt))
+			}
+		}
+		name := fmt.Sprintf("%d-%d.parquet", part[0].Number, part[len(part)-1].Number)
+		if err := s.writeFile(s.partitionPath("blocks", date, name), new(parquetBlock), blockRows); err != nil {
+			return err
+		}
+		if err := s.writeFile(s.partitionPath("transactions", date, name), new(parquetTransaction), txRows); err != nil {
+			return err
+		}
+		if err := s.writeFile(s.partitionPath("logs", date, name), new(parquetLog), logRows); err != nil {
+			return err
+		}
+		glog.Infof("Export parquet of %s %s: %d blocks, %d transactions, %d event logs", s.chain, date, len(blockRows), len(txRows), len(logRows))
+	}
+	return nil
+}
+
+func (s *ParquetSink) Close() error {
+	return nil
+}
+
+// path of an exported file in Hive-style partition of table, chain and date
+func (s *ParquetSink) partitionPath(table, date, name string) string {
+	path := fmt.Sprintf("%s/chain=%s/date=%s/%s", table, s.chain, date, name)
+	if len(s.prefix) > 0 {
+		path = s.prefix + "/" + path
+	}
+	return path
+}
+
+// encode rows of the schema object as Parquet, and write the result to the store
+func (s *ParquetSink) writeFile(path string, schema interface{}, rows []interface{}) error {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, schema, 1)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create parquet writer for %s", path)
+	}
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return errors.Wrapf(err, "Failed to write parquet row of %s", path)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return errors.Wrapf(err, "Failed to finish parquet file %s", path)
+	}
+	return s.store.WriteFile(path, buf.Bytes())
+}
+
+// group blocks by UTC date of block time, and sort blocks of each date by block number
+func partitionByDate(blocks map[string]*common.Block) map[string][]*common.Block {
+	result := make(map[string][]*common.Block)
+	for _, b := range blocks {
+		date := common.SecondsToDateTime(b.BlockTime).Format("2006-01-02")
+		result[date] = append(result[date], b)
+	}
+	for _, part := range result {
+		sort.Slice(part, func(i, j int) bool {
+			return part[i].Number < part[j].Number
+		})
+	}
+	return result
+}
+
+// convert Unix seconds to milliseconds of Parquet timestamp
+func secondsToMillis(t int64) int64 {
+	return t * 1000
+}
+
+func toParquetBlock(b *common.Block) *parquetBlock {
+	return &parquetBlock{
+		Hash:            b.Hash,
+		Number:          int64(b.Number),
+		ParentHash:      b.ParentHash.String(),
+		Miner:           b.Miner,
+		Difficulty:      common.BigIntToFloat(b.Difficulty),
+		ExactDifficulty: common.BigIntToString(b.Difficulty),
+		GasLimit:        int64(b.GasLimit),
+		GasUsed:         int64(b.GasUsed),
+		BlockTime:       secondsToMillis(b.BlockTime),
+	}
+}
+
+func toParquetTransaction(t *common.Transaction) *parquetTransaction {
+	return &parquetTransaction{
+		Hash:        t.Hash,
+		BlockNumber: int64(t.BlockNumber),
+		TxnIndex:    int64(t.TxnIndex),
+		Status:      t.Status,
+		From:        t.From,
+		To:          t.To,
+		Method:      t.Method,
+		Standard:    t.Standard,
+		Params:      toParquetParams(t.Params),
+		GasPrice:    int64(t.GasPrice),
+		Gas:         int64(t.Gas),
+		Value:       common.BigIntToFloat(t.Value),
+		ExactValue:  common.BigIntToString(t.Value),
+		Nonce:       int64(t.Nonce),
+		BlockTime:   secondsToMillis(t.BlockTime),
+	}
+}
+
+func toParquetLog(evt *common.EventLog) *parquetLog {
+	topics := evt.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return &parquetLog{
+		BlockNumber: int64(evt.BlockNumber),
+		LogIndex:    int64(evt.LogIndex),
+		Removed:     evt.Removed,
+		TxnIndex:    int64(evt.TxnIndex),
+		TxnHash:     evt.TxnHash,
+		Address:     evt.Address,
+		Topics:      topics,
+		Event:       evt.Event,
+		Standard:    evt.Standard,
+		Amount:      evt.Amount,
+		Params:      toParquetParams(evt.Params),
+		BlockTime:   secondsToMillis(evt.BlockTime),
+	}
+}
+
+func toParquetParams(params []*common.NamedValue) []parquetParam {
+	result := []parquetParam{}
+	for _, p := range params {
+		text, num := paramText(p)
+		v := parquetParam{
+			Name:  p.Name,
+			Kind:  p.Kind.String(),
+			Value: text,
+			Num:   num,
+		}
+		if d, ok := common.ParamToDecimal(p); ok {
+			v.Exact = &d
+		}
+		result = append(result, v)
+	}
+	return result
+}
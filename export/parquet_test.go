@@ -0,0 +1,98 @@
+package export
+
+// Run all unit test: `go test -v`
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// file store in memory for tests
+type memoryStore map[string][]byte
+
+func (m memoryStore) WriteFile(name string, content []byte) error {
+	m[name] = content
+	return nil
+}
+
+func sampleBlocks() map[string]*common.Block {
+	from := web3.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d")
+	hash := "0x5a4cba0f0ab5ab8b1f0d2d5e2e6a0bb0c9e0f0e6c7f3e7e0d6a9c1b2e3f4a5b6"
+	return map[string]*common.Block{hash: {
+		Hash:       hash,
+		Number:     13916166,
+		Miner:      "0x0000000000000000000000000000000000000001",
+		Difficulty: big.NewInt(1000),
+		BlockTime:  1640995200,
+		Transactions: map[string]*common.Transaction{"0x01": {
+			Hash:        "0x01",
+			BlockNumber: 13916166,
+			Method:      "transfer",
+			Standard:    "ERC20",
+			Params: []*common.NamedValue{
+				{Name: "to", Kind: abi.KindAddress, Value: from},
+				{Name: "value", Kind: abi.KindUInt, Value: big.NewInt(2500)},
+			},
+			Value:     big.NewInt(0),
+			BlockTime: 1640995200,
+		}},
+		Logs: map[uint64]*common.EventLog{0: {
+			BlockNumber: 13916166,
+			TxnHash:     "0x01",
+			Address:     "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			Topics:      []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+			Event:       "Transfer",
+			Standard:    "ERC20",
+			Params: []*common.NamedValue{
+				{Name: "from", Kind: abi.KindAddress, Value: from},
+				{Name: "data", Kind: abi.KindFixedBytes, Value: [4]byte{0xa9, 0x05, 0x9c, 0xbb}},
+			},
+			BlockTime: 1640995200,
+		}},
+	}}
+}
+
+func TestParquetSink(t *testing.T) {
+	store := memoryStore{}
+	sink := NewParquetSink(store, "lake", "ethereum")
+	require.NoError(t, sink.WriteBlocks(sampleBlocks()), "write blocks should not throw error")
+	assert.Equal(t, 3, len(store), "should export blocks, transactions and logs")
+
+	data, ok := store["lake/logs/chain=ethereum/date=2022-01-01/13916166-13916166.parquet"]
+	require.True(t, ok, "logs should be partitioned by chain and date")
+	file, err := buffer.NewBufferFile(data)
+	require.NoError(t, err, "open parquet buffer should not throw error")
+	pr, err := reader.NewParquetReader(file, new(parquetLog), 1)
+	require.NoError(t, err, "create parquet reader should not throw error")
+	defer pr.ReadStop()
+	require.Equal(t, int64(1), pr.GetNumRows(), "logs file should contain 1 row")
+
+	rows := make([]parquetLog, 1)
+	require.NoError(t, pr.Read(&rows), "read logs should not throw error")
+	assert.Equal(t, "Transfer", rows[0].Event, "event of exported log")
+	assert.Equal(t, int64(1640995200000), rows[0].BlockTime, "block time of exported log in millis")
+	require.Equal(t, 2, len(rows[0].Params), "params should be exported as repeated group")
+	assert.Equal(t, "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", rows[0].Params[0].Value, "address param as hex")
+	assert.Nil(t, rows[0].Params[0].Num, "address param should not have numeric value")
+	assert.Equal(t, "0xa9059cbb", rows[0].Params[1].Value, "fixed bytes param as hex")
+
+	data = store["lake/transactions/chain=ethereum/date=2022-01-01/13916166-13916166.parquet"]
+	file, err = buffer.NewBufferFile(data)
+	require.NoError(t, err, "open parquet buffer should not throw error")
+	tr, err := reader.NewParquetReader(file, new(parquetTransaction), 1)
+	require.NoError(t, err, "create parquet reader should not throw error")
+	defer tr.ReadStop()
+	txns := make([]parquetTransaction, 1)
+	require.NoError(t, tr.Read(&txns), "read transactions should not throw error")
+	require.Equal(t, 2, len(txns[0].Params), "transaction params should be exported")
+	assert.Equal(t, "2500", *txns[0].Params[1].Exact, "exact value of uint256 param")
+	assert.Equal(t, 2500.0, *txns[0].Params[1].Num, "numeric value of uint256 param")
+}
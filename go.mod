@@ -13,11 +13,15 @@ require (
 	github.com/jackc/pgx/v4 v4.16.0
 	github.com/mailru/go-clickhouse v1.7.0
 	github.com/mitchellh/mapstructure v1.4.1 // indirect
+	github.com/nats-io/nats-server/v2 v2.7.4
+	github.com/nats-io/nats.go v1.14.0
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.0
 	github.com/umbracle/ethgo v0.1.1
+	github.com/xitongsys/parquet-go v1.6.2
+	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
 	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd // indirect
-	golang.org/x/sync v0.0.0-20190423024810-112230192c58
+	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
 )
 
 // replace github.com/open-dovetail/eth-track => /Users/yxu/work/open-dovetail/eth-track
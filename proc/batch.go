@@ -0,0 +1,199 @@
+package proc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/multicall"
+	"github.com/open-dovetail/eth-track/contract/standard/safe"
+	web3 "github.com/umbracle/ethgo"
+)
+
+// max depth of nested batches to decode
+const maxBatchDepth = 4
+
+// inner call extracted from the params of a batch method
+type batchCall struct {
+	to       string
+	value    *big.Int
+	input    []byte
+	delegate bool // true if the call is a delegatecall that executes in the context of the caller
+}
+
+// extracts inner calls from the decoded params of a batch method invoked on a contract address
+type batchExtractor func(address string, dec *DecodedData) []*batchCall
+
+// batch methods keyed by method ID
+var batchMethods = make(map[string]batchExtractor)
+
+func init() {
+	for _, mth := range multicall.Multicall3Abi().Methods {
+		batchMethods[hex.EncodeToString(mth.ID())] = extractMulticall3
+	}
+	for _, mth := range multicall.MulticallAbi().Methods {
+		batchMethods[hex.EncodeToString(mth.ID())] = extractMulticall
+	}
+	batchMethods[hex.EncodeToString(safe.GnosisSafeAbi().Methods["execTransaction"].ID())] = extractSafeTransaction
+	batchMethods[hex.EncodeToString(safe.MultiSendAbi().Methods["multiSend"].ID())] = extractMultiSend
+}
+
+// return value of a named param, or nil if not found
+func paramValue(dec *DecodedData, name string) interface{} {
+	for _, p := range dec.Params {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+// Multicall3 calls of any target contracts, i.e., Call(target, callData), Call3(target, allowFailure, callData),
+// or Call3Value(target, allowFailure, value, callData)
+func extractMulticall3(address string, dec *DecodedData) []*batchCall {
+	calls, ok := paramValue(dec, "calls").([]map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var result []*batchCall
+	for _, c := range calls {
+		target, _ := c["target"].(web3.Address)
+		input, _ := c["callData"].([]byte)
+		value, _ := c["value"].(*big.Int)
+		result = append(result, &batchCall{
+			to:    strings.ToLower(target.String()),
+			value: value,
+			input: input,
+		})
+	}
+	return result
+}
+
+// Uniswap multicall of methods of the same contract, which delegatecalls the contract itself
+func extractMulticall(address string, dec *DecodedData) []*batchCall {
+	data, ok := paramValue(dec, "data").([][]byte)
+	if !ok {
+		return nil
+	}
+	var result []*batchCall
+	for _, input := range data {
+		result = append(result, &batchCall{
+			to:       address,
+			input:    input,
+			delegate: true,
+		})
+	}
+	return result
+}
+
+// Gnosis Safe execTransaction of a single call or delegatecall
+func extractSafeTransaction(address string, dec *DecodedData) []*batchCall {
+	to, ok := paramValue(dec, "to").(web3.Address)
+	if !ok {
+		return nil
+	}
+	input, _ := paramValue(dec, "data").([]byte)
+	value, _ := paramValue(dec, "value").(*big.Int)
+	operation, _ := paramValue(dec, "operation").(uint8)
+	return []*batchCall{{
+		to:       strings.ToLower(to.String()),
+		value:    value,
+		input:    input,
+		delegate: operation == 1,
+	}}
+}
+
+// Gnosis Safe multiSend of packed transactions, each encoded as
+// operation (1 byte), to (20 bytes), value (32 bytes), data length (32 bytes), and data
+func extractMultiSend(address string, dec *DecodedData) []*batchCall {
+	packed, ok := paramValue(dec, "transactions").([]byte)
+	if !ok {
+		return nil
+	}
+	var result []*batchCall
+	for i := 0; i+85 <= len(packed); {
+		to := web3.BytesToAddress(packed[i+1 : i+21])
+		value := new(big.Int).SetBytes(packed[i+21 : i+53])
+		size := new(big.Int).SetBytes(packed[i+53 : i+85])
+		start := i + 85
+		if !size.IsInt64() || size.Int64() > int64(len(packed)-start) {
+			glog.Warningf("Invalid multiSend data length %s at offset %d", size.String(), i)
+			break
+		}
+		end := start + int(size.Int64())
+		result = append(result, &batchCall{
+			to:       strings.ToLower(to.String()),
+			value:    value,
+			input:    packed[start:end],
+			delegate: packed[i] == 1,
+		})
+		i = end
+	}
+	return result
+}
+
+// decode inner calls of a batch transaction recursively against ABIs of the target contracts.
+// returns inner calls in depth-first order, or nil if the decoded method is not a batch method.
+// returns fatal error if failed to connect to etherscan or database
+func DecodeInnerCalls(txHash, address string, dec *DecodedData, blockNumber uint64, blockTime int64) ([]*common.InnerCall, error) {
+	return decodeBatch(txHash, address, address, dec, "", 0, blockNumber, blockTime, nil)
+}
+
+// decode inner calls of the batch method of the contract at address, which executes in the context of caller,
+// i.e., caller is the address itself, or the calling contract if the batch method is invoked by delegatecall,
+// e.g., a Safe that delegatecalls MultiSend.
+func decodeBatch(txHash, address, caller string, dec *DecodedData, prefix string, depth int, blockNumber uint64, blockTime int64, result []*common.InnerCall) ([]*common.InnerCall, error) {
+	if dec == nil || depth >= maxBatchDepth {
+		return result, nil
+	}
+	extract, ok := batchMethods[dec.ID]
+	if !ok {
+		return result, nil
+	}
+	for i, c := range extract(address, dec) {
+		call := &common.InnerCall{
+			TxnHash:     txHash,
+			Path:        prefix + strconv.Itoa(i),
+			BlockNumber: blockNumber,
+			From:        caller,
+			To:          c.to,
+			Operation:   common.CallOperation,
+			Input:       c.input,
+			Value:       c.value,
+			BlockTime:   blockTime,
+		}
+		// code of the target executes in the context of the caller for delegatecall, or of the target otherwise
+		context := c.to
+		if c.delegate {
+			call.Operation = common.DelegateCallOperation
+			context = caller
+		}
+		result = append(result, call)
+		if len(c.input) < 4 {
+			// no method call, e.g., ether transfer
+			continue
+		}
+		data, err := DecodeTransactionInput(c.input, c.to, blockNumber, blockTime)
+		if err != nil {
+			// fatal error
+			return result, err
+		}
+		if data == nil {
+			call.Method = "UNKNOWN"
+			continue
+		}
+		call.Method = data.Name
+		call.Standard = data.Standard
+		call.Params = data.Params
+		if glog.V(2) {
+			glog.Infof("Inner call %s %s: %s Method %s", txHash, call.Path, c.to, call.Method)
+		}
+		if result, err = decodeBatch(txHash, c.to, context, data, call.Path+".", depth+1, blockNumber, blockTime, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package proc
+
+// Run all unit test: `go test -v`
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc20"
+	"github.com/open-dovetail/eth-track/contract/standard/multicall"
+	"github.com/open-dovetail/eth-track/contract/standard/safe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+)
+
+func TestDecodeMulticall(t *testing.T) {
+	to := web3.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	transfer, err := erc20.ERC20Abi().Methods["transfer"].Encode([]interface{}{to, big.NewInt(100)})
+	require.NoError(t, err, "encode transfer should not throw error")
+	input, err := multicall.MulticallAbi().Methods["multicall"].Encode([]interface{}{[][]byte{transfer, {}}})
+	require.NoError(t, err, "encode multicall should not throw error")
+
+	addr := "0x0000000000000000000000000000000000000003"
+	dec, err := DecodeTransactionInput(input, addr, 0, -1)
+	require.NoError(t, err, "decode multicall should not throw error")
+	require.NotNil(t, dec, "multicall should be decoded")
+	assert.Equal(t, "Multicall", dec.Standard, "multicall should be decoded by standard method")
+
+	calls, err := DecodeInnerCalls("0x01", addr, dec, 0, -1)
+	require.NoError(t, err, "decode inner calls should not throw error")
+	require.Equal(t, 2, len(calls), "multicall should contain 2 inner calls")
+	assert.Equal(t, "0", calls[0].Path, "path of first inner call")
+	assert.Equal(t, addr, calls[0].To, "multicall target should be the same contract")
+	assert.Equal(t, "transfer", calls[0].Method, "inner call should be decoded as transfer")
+	assert.Equal(t, "ERC20", calls[0].Standard, "inner call should be decoded by standard method")
+	assert.Equal(t, "1", calls[1].Path, "path of second inner call")
+	assert.Empty(t, calls[1].Method, "empty inner call should not be decoded")
+}
+
+func TestExtractMultiSend(t *testing.T) {
+	to := web3.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	data := []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+	var packed bytes.Buffer
+	for i := 0; i < 2; i++ {
+		packed.WriteByte(byte(i))
+		packed.Write(to[:])
+		packed.Write(web3.BytesToHash(big.NewInt(int64(i)).Bytes()).Bytes())
+		packed.Write(web3.BytesToHash(big.NewInt(int64(len(data))).Bytes()).Bytes())
+		packed.Write(data)
+	}
+	dec := &DecodedData{Params: []*common.NamedValue{{Name: "transactions", Value: packed.Bytes()}}}
+	calls := extractMultiSend("", dec)
+	require.Equal(t, 2, len(calls), "multiSend should contain 2 transactions")
+	assert.Equal(t, "0x6b175474e89094c44da98b954eedeac495271d0f", calls[1].to, "target of multiSend transaction")
+	assert.Equal(t, int64(1), calls[1].value.Int64(), "value of multiSend transaction")
+	assert.Equal(t, data, calls[1].input, "data of multiSend transaction")
+	assert.False(t, calls[0].delegate, "operation 0 should be a call")
+	assert.True(t, calls[1].delegate, "operation 1 should be a delegatecall")
+}
+
+func TestSafeDelegateCall(t *testing.T) {
+	token := web3.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	transfer, err := erc20.ERC20Abi().Methods["transfer"].Encode([]interface{}{token, big.NewInt(100)})
+	require.NoError(t, err, "encode transfer should not throw error")
+
+	var packed bytes.Buffer
+	packed.WriteByte(0)
+	packed.Write(token[:])
+	packed.Write(web3.Hash{}.Bytes())
+	packed.Write(web3.BytesToHash(big.NewInt(int64(len(transfer))).Bytes()).Bytes())
+	packed.Write(transfer)
+	multiSend, err := safe.MultiSendAbi().Methods["multiSend"].Encode([]interface{}{packed.Bytes()})
+	require.NoError(t, err, "encode multiSend should not throw error")
+
+	multiSendAddr := web3.HexToAddress("0x40a2accbd92bca938b02010e17a5b8929b49130d")
+	input, err := safe.GnosisSafeAbi().Methods["execTransaction"].Encode([]interface{}{
+		multiSendAddr, big.NewInt(0), multiSend, uint8(1), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+		web3.ZeroAddress, web3.ZeroAddress, []byte{}})
+	require.NoError(t, err, "encode execTransaction should not throw error")
+
+	safeAddr := "0x0000000000000000000000000000000000000004"
+	dec, err := DecodeTransactionInput(input, safeAddr, 0, -1)
+	require.NoError(t, err, "decode execTransaction should not throw error")
+	require.NotNil(t, dec, "execTransaction should be decoded")
+
+	calls, err := DecodeInnerCalls("0x01", safeAddr, dec, 0, -1)
+	require.NoError(t, err, "decode inner calls should not throw error")
+	require.Equal(t, 2, len(calls), "execTransaction should contain multiSend and its transfer")
+	assert.Equal(t, common.DelegateCallOperation, calls[0].Operation, "Safe should delegatecall multiSend")
+	assert.Equal(t, safeAddr, calls[0].From, "multiSend should be called by the Safe")
+	assert.Equal(t, "0.0", calls[1].Path, "path of the transfer in multiSend")
+	assert.Equal(t, common.CallOperation, calls[1].Operation, "multiSend transaction should be a call")
+	assert.Equal(t, safeAddr, calls[1].From, "transfer should be sent by the Safe in the context of delegatecall")
+}
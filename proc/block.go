@@ -13,10 +13,35 @@ import (
 
 var blockDelay int
 
+// writer that copies staged blocks of multiple jobs to redshift at once, nil to store blocks of each job separately
+var blockWriter *redshift.BlockWriter
+
 func SetBlockDelay(delay int) {
 	blockDelay = delay
 }
 
+// sinks that receive decoded blocks of each block range, e.g., file export or message bus
+var blockSinks []common.BlockSink
+
+// add a sink of decoded blocks, which is written before the blocks are stored in redshift
+func AddBlockSink(sink common.BlockSink) {
+	blockSinks = append(blockSinks, sink)
+}
+
+// close all sinks of decoded blocks
+func CloseBlockSinks() {
+	for _, sink := range blockSinks {
+		if err := sink.Close(); err != nil {
+			glog.Warningf("Failed to close block sink: %v", err)
+		}
+	}
+}
+
+// if writer is not nil, decoded blocks are staged in s3, and copied to redshift by the writer with blocks of other jobs
+func SetBlockWriter(writer *redshift.BlockWriter) {
+	blockWriter = writer
+}
+
 // return block at the delayed height from the current block
 func LastConfirmedBlock() (*web3.Block, error) {
 	for retry := 1; retry <= 3; retry++ {
@@ -61,8 +86,30 @@ func DecodeBlockRange(hiBlock, lowBlock uint64) error {
 		blocks[block.Hash] = block
 	}
 
+	for _, sink := range blockSinks {
+		if err := sink.WriteBlocks(blocks); err != nil {
+			return errors.Wrapf(err, "Failed to write block range [%d, %d] to sink", lowBlock, hiBlock)
+		}
+	}
+
+	if !redshift.IsConnected() {
+		// sink-only mode without database
+		glog.Infof("Exported block range [%d, %d] - elapsed: %ds", lowBlock, hiBlock, (time.Now().Unix() - startTime))
+		return nil
+	}
+
+	s3Folder := strconv.FormatUint(hiBlock, 10)
+	if blockWriter != nil {
+		glog.Infof("Stage blocks of range [%d, %d]", lowBlock, hiBlock)
+		if err := redshift.StageBlocks(blocks, s3Folder); err != nil {
+			return err
+		}
+		glog.Infof("Decoded block range [%d, %d] - elapsed: %ds", lowBlock, hiBlock, (time.Now().Unix() - startTime))
+		return blockWriter.Add(s3Folder, redshift.Interval{Low: lowBlock, High: hiBlock})
+	}
+
 	glog.Infof("Store blocks of range [%d, %d]", lowBlock, hiBlock)
-	if err := redshift.StoreBlocks(blocks, strconv.FormatUint(hiBlock, 10)); err != nil {
+	if err := redshift.StoreBlocks(blocks, s3Folder); err != nil {
 		return err
 	}
 	glog.Infof("Decoded block range [%d, %d] - elapsed: %ds", lowBlock, hiBlock, (time.Now().Unix() - startTime))
@@ -132,8 +179,11 @@ func DecodeBlock(block *web3.Block) (*common.Block, error) {
 			}
 		}
 	}
-	err := DecodeEvents(result)
-	return result, err
+	if err := DecodeEvents(result); err != nil {
+		return result, err
+	}
+	JoinUserOperationEvents(result)
+	return result, nil
 }
 
 func DecodeEvents(b *common.Block) error {
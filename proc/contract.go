@@ -1,6 +1,7 @@
 package proc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"strings"
 
@@ -10,58 +11,243 @@ import (
 	"github.com/golang/glog"
 	"github.com/open-dovetail/eth-track/common"
 	"github.com/open-dovetail/eth-track/contract/standard/erc1155"
+	"github.com/open-dovetail/eth-track/contract/standard/erc165"
+	"github.com/open-dovetail/eth-track/contract/standard/erc20"
+	"github.com/open-dovetail/eth-track/contract/standard/erc2612"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4337"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4626"
 	"github.com/open-dovetail/eth-track/contract/standard/erc721"
 	"github.com/open-dovetail/eth-track/contract/standard/erc777"
+	"github.com/open-dovetail/eth-track/contract/standard/multicall"
+	"github.com/open-dovetail/eth-track/contract/standard/safe"
+	"github.com/open-dovetail/eth-track/contract/standard/weth9"
 	"github.com/open-dovetail/eth-track/redshift"
 	"github.com/pkg/errors"
 	web3 "github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/abi"
-	econ "github.com/umbracle/ethgo/contract"
 )
 
+// standard method with the name of the standard that defines it
+type stdMethod struct {
+	*abi.Method
+	Standard string // token standard or batch call wrapper, e.g., ERC20, ERC721, Multicall3, ERC4337
+}
+
+// method selector defined by both fungible and non-fungible token standards, e.g., approve and transferFrom
+type sharedMethod struct {
+	fungible    *stdMethod // method of a fungible token standard, e.g., ERC20
+	nonFungible *stdMethod // method of a non-fungible token standard, e.g., ERC721
+}
+
+var (
+	fungibleStandards    = map[string]bool{"ERC20": true, "ERC2612": true, "ERC4626": true, "WETH9": true, "ERC777": true}
+	nonFungibleStandards = map[string]bool{"ERC721": true, "ERC1155": true}
+)
+
+// return the method of the token standard of a contract, or the fungible method with blank standard
+// if the token standard is unknown, so the ambiguous selector is not labeled by a wrong standard.
+func (m *sharedMethod) match(tokenStandard string) (*abi.Method, string) {
+	if nonFungibleStandards[tokenStandard] {
+		return m.nonFungible.Method, m.nonFungible.Standard
+	}
+	if fungibleStandards[tokenStandard] {
+		return m.fungible.Method, m.fungible.Standard
+	}
+	return m.fungible.Method, ""
+}
+
+// standard event with the name of the token standard that defines it
+type stdEvent struct {
+	*abi.Event
+	Standard string // token standard, e.g., ERC20, ERC721
+}
+
+// names of ERC20 methods that are also defined by ERC777
+var erc20Methods = map[string]bool{
+	"name": true, "symbol": true, "decimals": true, "totalSupply": true, "balanceOf": true,
+	"transfer": true, "transferFrom": true, "approve": true, "allowance": true,
+}
+
 type contractMap struct {
 	sync.Mutex
-	stdMethods map[string]*abi.Method      // standard contract methods with ID as key
-	stdEvents  map[string]*abi.Event       // standard contract events with ID as key
+	stdMethods map[string]*stdMethod       // standard contract methods with ID as key
+	shared     map[string]*sharedMethod    // standard methods of the same ID in fungible and non-fungible token standards
+	stdEvents  map[string][]*stdEvent      // standard contract events with ID as key, variants differ by indexed params
 	contracts  map[string]*common.Contract // cached contracts by address
+	codeHashes map[string]*common.Contract // verified contracts by hash of runtime code
 	created    map[string]*common.Contract // new contracts pending db persistence
+	eoaBlocks  map[string]uint64           // block number of the last code check of cached EOA
+	backfilled map[string]bool             // cached contracts that have been checked for backfill of token standard
 }
 
 // singleton contract cache
 var contractCache *contractMap
 
+// number of blocks after which the code of a cached EOA is checked again, i.e., about 1 week of blocks
+const eoaRecheckBlocks = 50000
+
 func init() {
 	contractCache = &contractMap{
-		stdMethods: make(map[string]*abi.Method),
-		stdEvents:  make(map[string]*abi.Event),
+		stdMethods: make(map[string]*stdMethod),
+		shared:     make(map[string]*sharedMethod),
+		stdEvents:  make(map[string][]*stdEvent),
 		contracts:  make(map[string]*common.Contract),
+		codeHashes: make(map[string]*common.Contract),
 		created:    make(map[string]*common.Contract),
-	}
-	// set methods and events of standard ERC tokens
-	for _, ab := range []*abi.ABI{erc777.ERC777Abi(), erc721.ERC721Abi(), erc1155.ERC1155Abi()} {
-		for _, mth := range ab.Methods {
+		eoaBlocks:  make(map[string]uint64),
+		backfilled: make(map[string]bool),
+	}
+	// set methods and events of standard ERC tokens and batch call wrappers
+	// earlier standard takes precedence for methods and events of the same signature.
+	// ERC777 comes first, so ERC20 methods keep the param names of the ERC777 ABI that are stored in existing rows,
+	// e.g., transfer(recipient, amount), and they are labeled as ERC20 for backward compatibility.
+	standards := []struct {
+		abi      *abi.ABI
+		standard string
+	}{
+		{erc777.ERC777Abi(), "ERC777"},
+		{erc20.ERC20Abi(), "ERC20"},
+		{erc2612.ERC2612Abi(), "ERC2612"},
+		{erc4626.ERC4626Abi(), "ERC4626"},
+		{weth9.WETH9Abi(), "WETH9"},
+		{erc165.ERC165Abi(), "ERC165"},
+		{erc721.ERC721Abi(), "ERC721"},
+		{erc1155.ERC1155Abi(), "ERC1155"},
+		{multicall.Multicall3Abi(), "Multicall3"},
+		{multicall.MulticallAbi(), "Multicall"},
+		{safe.GnosisSafeAbi(), "GnosisSafe"},
+		{safe.MultiSendAbi(), "MultiSend"},
+		{erc4337.EntryPointAbi(), "ERC4337"},
+		{erc4337.EntryPointV7Abi(), "ERC4337"},
+	}
+	// first methods of fungible and non-fungible token standards with ID as key
+	fungible := make(map[string]*stdMethod)
+	nonFungible := make(map[string]*stdMethod)
+	for _, std := range standards {
+		for _, mth := range std.abi.Methods {
 			id := hex.EncodeToString(mth.ID())
+			standard := std.standard
+			if standard == "ERC777" && erc20Methods[mth.Name] {
+				// ERC777 tokens implement ERC20 methods for backward compatibility
+				standard = "ERC20"
+			}
+			m := &stdMethod{Method: mth, Standard: standard}
 			if _, ok := contractCache.stdMethods[id]; !ok {
-				contractCache.stdMethods[id] = mth
+				contractCache.stdMethods[id] = m
+			}
+			if _, ok := fungible[id]; !ok && fungibleStandards[std.standard] {
+				fungible[id] = m
+			}
+			if _, ok := nonFungible[id]; !ok && nonFungibleStandards[std.standard] {
+				nonFungible[id] = m
 			}
 		}
-		for _, evt := range ab.Events {
-			id := evt.ID().String()
-			if _, ok := contractCache.stdEvents[id]; !ok {
-				contractCache.stdEvents[id] = evt
+		for _, evt := range std.abi.Events {
+			standard := std.standard
+			if standard == "ERC777" && (evt.Name == "Transfer" || evt.Name == "Approval") {
+				// ERC777 tokens emit ERC20 events for backward compatibility
+				standard = "ERC20"
 			}
+			addStdEvent(evt, standard)
+		}
+	}
+	for id, m := range fungible {
+		if nf, ok := nonFungible[id]; ok && paramNames(m.Method) != paramNames(nf.Method) {
+			contractCache.shared[id] = &sharedMethod{fungible: m, nonFungible: nf}
 		}
 	}
 }
 
+// add a standard event unless a variant of the same indexed params is already registered
+func addStdEvent(evt *abi.Event, standard string) {
+	id := evt.ID().String()
+	for _, v := range contractCache.stdEvents[id] {
+		if indexedMask(v.Event) == indexedMask(evt) {
+			return
+		}
+	}
+	contractCache.stdEvents[id] = append(contractCache.stdEvents[id], &stdEvent{Event: evt, Standard: standard})
+}
+
+// return a string that flags indexed params of an event, e.g., '110' for Transfer(address indexed, address indexed, uint256)
+func indexedMask(evt *abi.Event) string {
+	var mask strings.Builder
+	for _, elem := range evt.Inputs.TupleElems() {
+		if elem.Indexed {
+			mask.WriteByte('1')
+		} else {
+			mask.WriteByte('0')
+		}
+	}
+	return mask.String()
+}
+
+// return true if the number of topics and data length of a log match the indexed and non-indexed params of an event
+func matchEventShape(evt *abi.Event, wlog *web3.Log) bool {
+	indexed, nonIndexed := 0, 0
+	dynamic := false
+	for _, elem := range evt.Inputs.TupleElems() {
+		if elem.Indexed {
+			indexed++
+			continue
+		}
+		nonIndexed++
+		switch elem.Elem.Kind() {
+		case abi.KindSlice, abi.KindBytes, abi.KindString, abi.KindTuple, abi.KindArray:
+			// size of data cannot be determined by the number of params
+			dynamic = true
+		}
+	}
+	if !evt.Anonymous {
+		indexed++
+	}
+	if len(wlog.Topics) != indexed {
+		return false
+	}
+	if dynamic {
+		return len(wlog.Data) >= 32*nonIndexed
+	}
+	return len(wlog.Data) == 32*nonIndexed
+}
+
+// return the standard event that matches the shape of a log, or nil if no standard event matches
+func findStdEvent(wlog *web3.Log) *stdEvent {
+	for _, v := range contractCache.stdEvents[wlog.Topics[0].String()] {
+		if matchEventShape(v.Event, wlog) {
+			return v
+		}
+	}
+	return nil
+}
+
 // return a contract by (1) lookup in-memory cache; (2) quey database; (3) fetch from etherscan.
+// if blockNumber > 0, check the code at the block, and return an EOA without calling etherscan if code is empty.
+// return ErrBudgetExceeded if etherscan lookup is deferred due to exceeded daily budget, so the block is decoded again later.
 // return fatal error if failed to connect to etherscan or save batched contracts to database.
-func getContract(address string, blockTime int64) (*common.Contract, error) {
+func getContract(address string, blockNumber uint64, blockTime int64) (*common.Contract, error) {
 	contractCache.Lock()
 	defer contractCache.Unlock()
 
+	return findContract(address, blockNumber, blockTime, false)
+}
+
+// return the contract that emitted an event log at a block, which is never an EOA.
+// a contract created and self-destructed in the same block has no code at the block, but it is still a contract.
+func getLogContract(address string, blockNumber uint64, blockTime int64) (*common.Contract, error) {
+	contractCache.Lock()
+	defer contractCache.Unlock()
+
+	return findContract(address, blockNumber, blockTime, true)
+}
+
+// same as getContract, but must be called while holding the lock of contractCache.
+// if emitter is true, the address emitted an event log, so it is not classified as an EOA.
+func findContract(address string, blockNumber uint64, blockTime int64, emitter bool) (*common.Contract, error) {
 	// find cached contract
 	if contract, ok := contractCache.contracts[address]; ok {
+		if isStaleEOA(contract, blockNumber) || (emitter && contract.AddressType == common.EOAAddress) {
+			return recheckEOA(contract, blockNumber, blockTime, emitter)
+		}
 		if glog.V(2) {
 			glog.Infof("Found cached contract ABI for address %s Symbol %s methods=%d events=%d", address, contract.Symbol, len(contract.Methods), len(contract.Events))
 		}
@@ -71,7 +257,19 @@ func getContract(address string, blockTime int64) (*common.Contract, error) {
 	// fetch contract from db
 	if contract, err := redshift.QueryContract(address); contract != nil && err == nil {
 		contractCache.contracts[address] = contract
-		parseABI(contract)
+		if contract.AddressType == common.EOAAddress {
+			if emitter {
+				return recheckEOA(contract, blockNumber, blockTime, emitter)
+			}
+			// trust stored EOA until it is checked again after eoaRecheckBlocks
+			if blockNumber > 0 {
+				contractCache.eoaBlocks[address] = blockNumber
+			}
+			return contract, nil
+		}
+		if parseABI(contract) == nil {
+			cacheCodeHash(contract)
+		}
 		if glog.V(1) {
 			glog.Infof("Query returned contract for address %s Symbol %s methods=%d events=%d", address, contract.Symbol, len(contract.Methods), len(contract.Events))
 		}
@@ -79,7 +277,69 @@ func getContract(address string, blockTime int64) (*common.Contract, error) {
 	}
 
 	// create new contract
-	return newContract(address, blockTime)
+	return newContract(address, blockNumber, blockTime, emitter)
+}
+
+// return true if code of a cached EOA was checked more than eoaRecheckBlocks before the specified block,
+// so its code must be checked again, e.g., for CREATE2 deployment or counterfactual ERC-4337 account.
+// EOA loaded from db without a checked block is assumed to be checked at the first block it is used.
+// must be called while holding the lock of contractCache
+func isStaleEOA(contract *common.Contract, blockNumber uint64) bool {
+	if contract.AddressType != common.EOAAddress || blockNumber == 0 {
+		return false
+	}
+	checked, ok := contractCache.eoaBlocks[contract.Address]
+	if !ok {
+		contractCache.eoaBlocks[contract.Address] = blockNumber
+		return false
+	}
+	return blockNumber > checked+eoaRecheckBlocks
+}
+
+// check code of a cached EOA again, and replace it by a new contract if code is deployed at the address,
+// or if the address emitted an event log.
+// must be called while holding the lock of contractCache
+func recheckEOA(contract *common.Contract, blockNumber uint64, blockTime int64, emitter bool) (*common.Contract, error) {
+	code, err := contractCode(contract.Address, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 && !emitter {
+		contractCache.eoaBlocks[contract.Address] = blockNumber
+		return contract, nil
+	}
+	glog.Infof("Contract is deployed at EOA %s by block %d", contract.Address, blockNumber)
+	delete(contractCache.contracts, contract.Address)
+	delete(contractCache.eoaBlocks, contract.Address)
+	// new contract replaces the stored EOA of the same address
+	return newContract(contract.Address, blockNumber, blockTime, emitter)
+}
+
+// return comma-separated input param names of a method, e.g., "spender,value" of ERC20 approve
+func paramNames(mth *abi.Method) string {
+	var names []string
+	for _, elem := range mth.Inputs.TupleElems() {
+		names = append(names, elem.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// return token standard of a contract, which may be updated concurrently, or blank if contract is nil
+func tokenStandard(contract *common.Contract) string {
+	if contract == nil {
+		return ""
+	}
+	contractCache.Lock()
+	defer contractCache.Unlock()
+	return contract.TokenStandard
+}
+
+// return a contract from in-memory cache, or nil if it is not cached
+func cachedContract(address string) *common.Contract {
+	contractCache.Lock()
+	defer contractCache.Unlock()
+
+	return contractCache.contracts[address]
 }
 
 // query and cache contracts used in recent days -- used when restart the decode engine
@@ -100,14 +360,18 @@ func CacheContracts(days int) error {
 			glog.Infof("cache contract [%d] %s", iter, contract.Address)
 		}
 		contractCache.contracts[contract.Address] = contract
-		if len(contract.ABI) > 0 {
-			parseABI(contract)
+		if len(contract.ABI) > 0 && parseABI(contract) == nil {
+			cacheCodeHash(contract)
 		}
 	}
 	return nil
 }
 
 func setContractEventTime(contract *common.Contract, blockTime int64) {
+	if contract.AddressType == common.EOAAddress {
+		// do not track event time of EOA
+		return
+	}
 	eventTime := common.RoundToUTCDate(blockTime)
 	if eventTime <= contract.LastEventDate {
 		// update only for new event date
@@ -122,6 +386,10 @@ func setContractEventTime(contract *common.Contract, blockTime int64) {
 }
 
 func setContractErrorTime(contract *common.Contract, blockTime int64) {
+	if contract.AddressType == common.EOAAddress {
+		// do not track error time of EOA, which has no methods or events
+		return
+	}
 	eventTime := common.RoundToUTCDate(blockTime)
 	if eventTime <= contract.LastErrorDate {
 		// update only for new error date
@@ -138,33 +406,74 @@ func setContractErrorTime(contract *common.Contract, blockTime int64) {
 	}
 }
 
-// create new contract by fetching ABI from etherscan
+// create new contract by fetching ABI from etherscan.
+// an address without code at the block is stored as an EOA, unless it is the emitter of an event log.
+// return ErrBudgetExceeded if etherscan daily budget is exceeded, so the lookup is deferred to a later call
 // return fatal error if failed to connect to etherscan or save to database
-func newContract(address string, blockTime int64) (*common.Contract, error) {
+func newContract(address string, blockNumber uint64, blockTime int64, emitter bool) (*common.Contract, error) {
 	eventTime := common.RoundToUTCDate(blockTime)
 	contract := &common.Contract{
 		Address:       address,
 		LastEventDate: eventTime,
 	}
 
-	// Fetch ABI from etherscan - retry 10 times on etherscan failure
+	code, err := contractCode(address, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 && blockNumber > 0 && !emitter {
+		// do not call etherscan for EOA
+		contract.AddressType = common.EOAAddress
+		if glog.V(1) {
+			glog.Infof("Created new EOA %s at block %d", address, blockNumber)
+		}
+		// store EOA, whose code is checked again after eoaRecheckBlocks, since a contract may be deployed later
+		contractCache.eoaBlocks[address] = blockNumber
+		return contract, cacheNewContract(contract)
+	}
+	contract.AddressType = common.ContractAddress
+	if len(code) > 0 {
+		contract.CodeHash = hex.EncodeToString(web3.Keccak256(code))
+		contract.CloneTarget = minimalProxyTarget(code)
+	}
+
+	// reuse ABI of a known verified contract of the same code or clone target
+	known, err := findKnownContract(contract, blockNumber, blockTime)
+	if err != nil {
+		return nil, err
+	}
+	if known != nil {
+		copySourceCode(contract, known)
+		return completeContract(contract, eventTime)
+	}
+
+	// Fetch ABI and source metadata from etherscan - retry 10 times on etherscan failure
+	var source *SourceCode
 	for retry := 1; retry <= 10; retry++ {
-		if data, err := FetchABI(address, 0); err == nil {
-			contract.ABI = data
+		if data, err := FetchSourceCode(address, 0); err == nil {
+			source = data
 			break
+		} else if errors.Is(err, ErrBudgetExceeded) {
+			// defer ABI lookup till budget is available, and do not cache the contract
+			return nil, errors.Wrapf(err, "Deferred ABI lookup for contract %s", address)
 		} else {
 			// Etherscan connection down, wait and retry
 			glog.Warningf("Etherscan API failed %d times for address %s: %+v", retry, address, err)
 			time.Sleep(time.Duration(10*retry) * time.Second)
 		}
 	}
-	if len(contract.ABI) == 0 {
+	if source == nil {
 		glog.Errorf("Failed to fetch ABI from etherscan for contract %s", address)
 		return nil, errors.Errorf("Failed to fetch ABI from etherscan for contract %s", address)
 	}
+	setSourceCode(contract, source)
+	return completeContract(contract, eventTime)
+}
 
-	updateERC20Properties(contract)
-	contractCache.contracts[address] = contract
+// set token properties and parse ABI of a new contract, then cache the contract
+func completeContract(contract *common.Contract, eventTime int64) (*common.Contract, error) {
+	isERC20 := updateERC20Properties(contract)
+	contract.TokenStandard = detectTokenStandard(contract.Address, isERC20)
 
 	// parse ABI to set definitions of methods and events
 	if err := parseABI(contract); err != nil {
@@ -174,26 +483,170 @@ func newContract(address string, blockTime int64) (*common.Contract, error) {
 		// do not store invalid ABI
 		contract.ABI = ""
 		contract.LastErrorDate = eventTime
+	} else {
+		cacheCodeHash(contract)
 	}
 	if glog.V(1) {
-		glog.Infof("Created new contract %s Symbol %s methods=%d events=%d", address, contract.Symbol, len(contract.Methods), len(contract.Events))
+		glog.Infof("Created new contract %s Name %s Symbol %s Standard %s methods=%d events=%d", contract.Address, contract.ContractName, contract.Symbol, contract.TokenStandard, len(contract.Methods), len(contract.Events))
 	}
+	return contract, cacheNewContract(contract)
+}
 
-	// store new contracts to db in batches
-	contractCache.created[address] = contract
+// cache a new contract, and store new contracts to db in batches
+// return fatal error if failed to save the batch to database
+func cacheNewContract(contract *common.Contract) error {
+	contractCache.contracts[contract.Address] = contract
+	contractCache.created[contract.Address] = contract
 	if len(contractCache.created) >= 200 {
 		//fmt.Println("Save new contracts", len(contractCache.contracts), len(contractCache.created))
 		if err := redshift.StoreContracts(contractCache.created); err != nil {
 			// return error if failed to save the batch
 			glog.Errorf("Failed to save %d contracts: %v", len(contractCache.created), err)
-			return nil, errors.Wrapf(err, "Failed to save %d contracts", len(contractCache.created))
+			return errors.Wrapf(err, "Failed to save %d contracts", len(contractCache.created))
 		}
 		if glog.V(1) {
 			glog.Infof("Saved %d contracts", len(contractCache.created))
 		}
 		contractCache.created = make(map[string]*common.Contract)
 	}
-	return contract, nil
+	return nil
+}
+
+// store new contracts that are pending in the cache, e.g., before exit
+func SaveContracts() error {
+	contractCache.Lock()
+	defer contractCache.Unlock()
+
+	if len(contractCache.created) == 0 {
+		return nil
+	}
+	if err := redshift.StoreContracts(contractCache.created); err != nil {
+		return errors.Wrapf(err, "Failed to save %d contracts", len(contractCache.created))
+	}
+	glog.Infof("Saved %d contracts", len(contractCache.created))
+	contractCache.created = make(map[string]*common.Contract)
+	return nil
+}
+
+// return a verified contract that is the target of an EIP-1167 minimal proxy,
+// or has the same runtime code as the specified contract.
+// returns nil if no such contract is known.
+// returns fatal error if failed to connect to etherscan or database.
+func findKnownContract(contract *common.Contract, blockNumber uint64, blockTime int64) (*common.Contract, error) {
+	if len(contract.CloneTarget) > 0 && contract.CloneTarget != contract.Address {
+		target, err := findContract(contract.CloneTarget, blockNumber, blockTime, false)
+		if err != nil {
+			return nil, err
+		}
+		if target != nil && len(target.ABI) > 0 {
+			if glog.V(1) {
+				glog.Infof("Use ABI of clone target %s for contract %s", target.Address, contract.Address)
+			}
+			return target, nil
+		}
+	}
+	if len(contract.CodeHash) == 0 {
+		return nil, nil
+	}
+	if known, ok := contractCache.codeHashes[contract.CodeHash]; ok {
+		if glog.V(1) {
+			glog.Infof("Use ABI of contract %s of the same code for contract %s", known.Address, contract.Address)
+		}
+		return known, nil
+	}
+	if known, err := redshift.QueryContractByCodeHash(contract.CodeHash); known != nil && err == nil {
+		if parseABI(known) == nil {
+			cacheCodeHash(known)
+			if glog.V(1) {
+				glog.Infof("Use ABI of stored contract %s of the same code for contract %s", known.Address, contract.Address)
+			}
+			return known, nil
+		}
+	}
+	return nil, nil
+}
+
+// index a contract of valid ABI by its code hash
+func cacheCodeHash(contract *common.Contract) {
+	if len(contract.CodeHash) > 0 && len(contract.ABI) > 0 {
+		if _, ok := contractCache.codeHashes[contract.CodeHash]; !ok {
+			contractCache.codeHashes[contract.CodeHash] = contract
+		}
+	}
+}
+
+// EIP-1167 minimal proxy runtime code is prefix + 20-byte target address + suffix
+var (
+	minimalProxyPrefix, _ = hex.DecodeString("363d3d373d3d3d363d73")
+	minimalProxySuffix, _ = hex.DecodeString("5af43d82803e903d91602b57fd5bf3")
+)
+
+// return target address if code is an EIP-1167 minimal proxy, or blank otherwise
+func minimalProxyTarget(code []byte) string {
+	if len(code) != len(minimalProxyPrefix)+20+len(minimalProxySuffix) {
+		return ""
+	}
+	if !bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return ""
+	}
+	target := code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+20]
+	return "0x" + hex.EncodeToString(target)
+}
+
+// return runtime code of an address at a block, or before the block if the contract self-destructed in the block.
+// code is empty for EOA, and for a contract created and self-destructed in the same block.
+// return fatal error if failed to connect to Ethereum node
+func contractCode(address string, blockNumber uint64) ([]byte, error) {
+	code, err := getCode(address, blockNumber)
+	if err != nil || len(code) > 0 || blockNumber <= 1 {
+		return code, err
+	}
+	return getCode(address, blockNumber-1)
+}
+
+// return runtime code of an address at a block, which is empty for EOA
+// return fatal error if failed to connect to Ethereum node
+func getCode(address string, blockNumber uint64) ([]byte, error) {
+	client := GetEthereumClient()
+	if client == nil {
+		return nil, errors.New("Ethereum client is not initialized")
+	}
+	block := web3.Latest
+	if blockNumber > 0 {
+		block = web3.BlockNumber(blockNumber)
+	}
+	for retry := 1; retry <= 3; retry++ {
+		if code, err := client.Eth().GetCode(web3.HexToAddress(address), block); err == nil {
+			return hex.DecodeString(strings.TrimPrefix(code, "0x"))
+		} else {
+			// Ethereum call failed, wait and retry
+			glog.Warningf("Failed %d times to get code of address %s at block %d: %+v", retry, address, blockNumber, err)
+			time.Sleep(10 * time.Second)
+		}
+	}
+	return nil, errors.Errorf("Failed to get code of address %s at block %d", address, blockNumber)
+}
+
+// set ABI and source metadata of a contract
+func setSourceCode(c *common.Contract, source *SourceCode) {
+	c.ABI = source.ABI
+	c.ContractName = source.ContractName
+	c.Compiler = source.CompilerVersion
+	c.Optimized = source.Optimized
+	c.OptimizerRuns = source.OptimizerRuns
+	c.License = source.License
+	c.IsProxy = source.IsProxy
+	c.Implementation = source.Implementation
+}
+
+// copy ABI and source metadata from a known contract
+func copySourceCode(c *common.Contract, known *common.Contract) {
+	c.ABI = known.ABI
+	c.ContractName = known.ContractName
+	c.Compiler = known.Compiler
+	c.Optimized = known.Optimized
+	c.OptimizerRuns = known.OptimizerRuns
+	c.License = known.License
 }
 
 func parseABI(c *common.Contract) error {
@@ -226,54 +679,85 @@ func parseABI(c *common.Contract) error {
 	return nil
 }
 
-func updateERC20Properties(c *common.Contract) {
-	if client := GetEthereumClient(); client != nil {
-		// try to set ERC token properties
-		token := erc777.NewERC777(web3.HexToAddress(c.Address), econ.WithJsonRPC(client.Eth()))
-		if dec, err := token.Decimals(); err == nil {
-			c.Decimals = dec
-		}
-		if name, err := token.Name(); err == nil {
-			c.Name = name
-		}
-		if symbol, err := token.Symbol(); err == nil {
-			c.Symbol = symbol
-		}
-		if totalSupply, err := token.TotalSupply(); err == nil {
-			c.TotalSupply = common.BigIntToFloat(totalSupply)
-		}
-	}
-}
-
 // remove cached contract last accessed earlier than minAccessTime
 func CleanupContractCache(minAccessTime int64) {
+	contractCache.Lock()
+	defer contractCache.Unlock()
+
 	for k, v := range contractCache.contracts {
 		if v.LastEventDate < minAccessTime {
 			delete(contractCache.contracts, k)
+			delete(contractCache.eoaBlocks, k)
+			delete(contractCache.backfilled, k)
+		}
+	}
+	for k, v := range contractCache.codeHashes {
+		if v.LastEventDate < minAccessTime {
+			delete(contractCache.codeHashes, k)
 		}
 	}
 }
 
 type DecodedData struct {
-	Name   string // name of method or event
-	ID     string // ID of method or event
-	Params []*common.NamedValue
+	Name     string // name of method or event
+	ID       string // ID of method or event
+	Standard string // standard of the standard method or event used for decoding, blank if decoded by contract ABI
+	Params   []*common.NamedValue
 }
 
 // decode transaction input of a specified contract.
-// returns decoded result if decode is successful, or nil otherwise
+// returns decoded result if decode is successful, empty result if the address is an EOA, or nil otherwise
 // returns fatal error if failed to connect to etherscan or database for the operation
-func DecodeTransactionInput(input []byte, address string, blockTime int64) (*DecodedData, error) {
-	var contract *common.Contract
+func DecodeTransactionInput(input []byte, address string, blockNumber uint64, blockTime int64) (*DecodedData, error) {
 	methodID := hex.EncodeToString(input[:4])
-	method, ok := contractCache.stdMethods[methodID]
-	if !ok {
+	var method *abi.Method
+	var standard string
+
+	// prefer method of a cached contract ABI, so same-selector methods of different params are decoded correctly
+	contract := cachedContract(address)
+	if contract != nil {
+		method = contract.Methods[methodID]
+	}
+	if shared, ok := contractCache.shared[methodID]; ok && method == nil {
+		// selector of both fungible and non-fungible token standards, so check token standard of the contract
+		if contract == nil {
+			var err error
+			if contract, err = getContract(address, blockNumber, blockTime); err != nil {
+				return nil, err
+			}
+		}
+		if contract != nil && contract.AddressType == common.EOAAddress {
+			// input data of a transfer to EOA is not a method call
+			return &DecodedData{Params: []*common.NamedValue{}}, nil
+		}
+		if contract != nil {
+			method = contract.Methods[methodID]
+		}
+		if method == nil {
+			method, standard = shared.match(tokenStandard(contract))
+			contract = nil
+		}
+	}
+	if method == nil && (contract == nil || contract.AddressType != common.EOAAddress) {
+		// try standard method
+		if std, ok := contractCache.stdMethods[methodID]; ok {
+			method = std.Method
+			standard = std.Standard
+			contract = nil
+		}
+	}
+	if method == nil {
 		// find contract method
 		var err error
-		contract, err = getContract(address, blockTime)
-		if err != nil {
+		var ok bool
+		contract, err = getContract(address, blockNumber, blockTime)
+		if err != nil || contract == nil {
 			return nil, err
 		}
+		if contract.AddressType == common.EOAAddress {
+			// input data of a transfer to EOA is not a method call
+			return &DecodedData{Params: []*common.NamedValue{}}, nil
+		}
 		if len(contract.Methods) == 0 {
 			if glog.V(1) {
 				glog.Infof("Contract 0x%s contains no method %s", address, methodID)
@@ -299,9 +783,10 @@ func DecodeTransactionInput(input []byte, address string, blockTime int64) (*Dec
 			glog.Warningf("Transaction contains no input data for contract %s method %s", address, methodID)
 		}
 		return &DecodedData{
-			Name:   method.Name,
-			ID:     methodID,
-			Params: []*common.NamedValue{},
+			Name:     method.Name,
+			ID:       methodID,
+			Standard: standard,
+			Params:   []*common.NamedValue{},
 		}, nil
 	}
 	data, err := safeAbiDecode(method.Inputs, input[4:])
@@ -318,16 +803,10 @@ func DecodeTransactionInput(input []byte, address string, blockTime int64) (*Dec
 		return nil, nil
 	}
 	dec := &DecodedData{
-		Name:   method.Name,
-		ID:     methodID,
-		Params: []*common.NamedValue{},
-	}
-	for _, elem := range method.Inputs.TupleElems() {
-		dec.Params = append(dec.Params, &common.NamedValue{
-			Name:  elem.Name,
-			Kind:  elem.Elem.Kind(),
-			Value: dmap[elem.Name],
-		})
+		Name:     method.Name,
+		ID:       methodID,
+		Standard: standard,
+		Params:   namedParams(method.Inputs, dmap),
 	}
 	if contract != nil {
 		setContractEventTime(contract, blockTime)
@@ -365,20 +844,23 @@ func DecodeEventData(wlog *web3.Log, blockTime int64) (*DecodedData, error) {
 	var event *abi.Event
 	var ok bool
 	var data map[string]interface{}
+	var standard string
 
-	if event, ok = contractCache.stdEvents[eventID]; ok {
-		// try to parse w/ standard event
+	if std := findStdEvent(wlog); std != nil {
+		// try to parse w/ standard event of matching topics and data
 		var err error
-		if data, err = event.ParseLog(wlog); err != nil {
-			// not a standard event
-			ok = false
+		if data, err = std.ParseLog(wlog); err == nil {
+			event = std.Event
+			standard = std.Standard
+			ok = true
 		}
 	}
 	if !ok {
 		// find contract event
 		addr := strings.ToLower(wlog.Address.String())
-		contract, err := getContract(addr, blockTime)
-		if err != nil {
+		// check code at the block of the log, so a contract that self-destructed later still gets its code hash and clone target
+		contract, err := getLogContract(addr, wlog.BlockNumber, blockTime)
+		if err != nil || contract == nil {
 			return nil, err
 		}
 		if len(contract.Events) == 0 {
@@ -410,16 +892,15 @@ func DecodeEventData(wlog *web3.Log, blockTime int64) (*DecodedData, error) {
 	}
 
 	dec := &DecodedData{
-		Name:   event.Name,
-		ID:     eventID,
-		Params: []*common.NamedValue{},
-	}
-	for _, elem := range event.Inputs.TupleElems() {
-		dec.Params = append(dec.Params, &common.NamedValue{
-			Name:  elem.Name,
-			Kind:  elem.Elem.Kind(),
-			Value: data[elem.Name],
-		})
+		Name:     event.Name,
+		ID:       eventID,
+		Standard: standard,
+		Params:   namedParams(event.Inputs, data),
+	}
+
+	// register child contract created by a known factory
+	if err := registerFactoryChild(strings.ToLower(wlog.Address.String()), dec, blockTime); err != nil {
+		return nil, err
 	}
 	return dec, nil
 }
@@ -21,7 +21,7 @@ func TestContract(t *testing.T) {
 	expected := [][]int{{22, 3}, {32, 11}, {5, 2}}
 
 	for i, addr := range addrs {
-		c, err := newContract(addr, -1)
+		c, err := newContract(addr, 0, -1, false)
 		assert.NoError(t, err, "Error retrieving contract: %s", addr)
 		assert.Equal(t, expected[i][0], len(c.Methods), "contract %s should contain %d methods", addr, expected[i][0])
 		assert.Equal(t, expected[i][1], len(c.Events), "contract %s should contain %d events", addr, expected[i][1])
@@ -30,6 +30,34 @@ func TestContract(t *testing.T) {
 	//assert.NoError(t, err, "save contracts should not throw error")
 }
 
+func TestEOAContract(t *testing.T) {
+	addr := "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+	c, err := newContract(addr, 13648277, -1, false)
+	require.NoError(t, err, "Error retrieving contract: %s", addr)
+	assert.Equal(t, common.EOAAddress, c.AddressType, "address %s should be an EOA", addr)
+	assert.Empty(t, c.ABI, "EOA %s should not contain ABI", addr)
+	_, saved := contractCache.created[addr]
+	assert.True(t, saved, "EOA %s should be stored in db", addr)
+	assert.False(t, isStaleEOA(c, 13648278), "EOA %s should not be checked again at the next block", addr)
+	assert.True(t, isStaleEOA(c, 13648277+eoaRecheckBlocks+1), "EOA %s should be checked again after eoaRecheckBlocks", addr)
+	setContractErrorTime(c, 1640995200)
+	assert.Zero(t, c.LastErrorDate, "error time of EOA %s should not be updated", addr)
+
+	dec, err := DecodeTransactionInput([]byte{1, 2, 3, 4, 5}, addr, 13648277, -1)
+	require.NoError(t, err, "decode transaction to EOA should not throw exception")
+	assert.Empty(t, dec.Name, "transaction to EOA should not decode a method")
+}
+
+func TestMinimalProxyTarget(t *testing.T) {
+	target := "0xbebc44782c7db0a1a60cb6fe97d0b483032ff1c7"
+	code, err := hex.DecodeString("363d3d373d3d3d363d73" + target[2:] + "5af43d82803e903d91602b57fd5bf3")
+	require.NoError(t, err, "clone code should be valid hex")
+	assert.Equal(t, target, minimalProxyTarget(code), "should return target address of EIP-1167 clone")
+	assert.Empty(t, minimalProxyTarget(code[1:]), "should not return target for code of wrong length")
+	code[0] = 0x60
+	assert.Empty(t, minimalProxyTarget(code), "should not return target for code of wrong prefix")
+}
+
 // This test gets source code of a contract from etherscan, although it returns only compiled code
 //   it maybe useful if adding contract decompiling and abi generation
 func TestGetCode(t *testing.T) {
@@ -62,3 +90,111 @@ func TestDecodeTransaction(t *testing.T) {
 	assert.Error(t, err, "transaction decode should catch panic error")
 	assert.Nil(t, decoded, "tranction decode should return no data")
 }
+
+func TestStdEventVariants(t *testing.T) {
+	transfer := web3.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	from := web3.HexToHash("0x000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	to := web3.HexToHash("0x000000000000000000000000dac17f958d2ee523a2206206994597c13d831ec7")
+	value := web3.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000064")
+
+	erc20Log := &web3.Log{Topics: []web3.Hash{transfer, from, to}, Data: value[:]}
+	std := findStdEvent(erc20Log)
+	require.NotNil(t, std, "ERC20 transfer should match a standard event")
+	assert.Equal(t, "ERC20", std.Standard, "transfer with 3 topics should be decoded as ERC20")
+
+	erc721Log := &web3.Log{Topics: []web3.Hash{transfer, from, to, value}}
+	std = findStdEvent(erc721Log)
+	require.NotNil(t, std, "ERC721 transfer should match a standard event")
+	assert.Equal(t, "ERC721", std.Standard, "transfer with 4 topics should be decoded as ERC721")
+
+	badLog := &web3.Log{Topics: []web3.Hash{transfer, from}, Data: value[:]}
+	assert.Nil(t, findStdEvent(badLog), "transfer with 2 topics should not match a standard event")
+}
+
+func TestPreferContractMethod(t *testing.T) {
+	// ERC20 transfer(address,uint256) with the same selector as the standard method but different param names
+	input, err := hex.DecodeString("a9059cbb000000000000000000000000dac17f958d2ee523a2206206994597c13d831ec70000000000000000000000000000000000000000000000000000000000000064")
+	require.NoError(t, err, "input data should be valid hex")
+
+	dec, err := DecodeTransactionInput(input, "0x0000000000000000000000000000000000000001", 0, -1)
+	require.NoError(t, err, "decode standard method should not throw error")
+	require.NotNil(t, dec, "standard method should be decoded")
+	assert.Equal(t, "ERC20", dec.Standard, "uncached contract should be decoded by standard method")
+
+	addr := "0x0000000000000000000000000000000000000002"
+	contract := &common.Contract{
+		Address:       addr,
+		AddressType:   common.ContractAddress,
+		LastEventDate: common.RoundToUTCDate(-1),
+		ABI:           `[{"constant":false,"inputs":[{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`,
+	}
+	require.NoError(t, parseABI(contract), "contract ABI should be parsed")
+	contractCache.Lock()
+	contractCache.contracts[addr] = contract
+	contractCache.Unlock()
+	t.Cleanup(func() {
+		contractCache.Lock()
+		delete(contractCache.contracts, addr)
+		contractCache.Unlock()
+	})
+
+	dec, err = DecodeTransactionInput(input, addr, 0, -1)
+	require.NoError(t, err, "decode contract method should not throw error")
+	require.NotNil(t, dec, "contract method should be decoded")
+	assert.Empty(t, dec.Standard, "cached contract should be decoded by its own ABI")
+	assert.Equal(t, "dst", dec.Params[0].Name, "param name should come from contract ABI")
+}
+
+func TestStdMethods(t *testing.T) {
+	expected := map[string]string{
+		"a9059cbb": "ERC20",   // transfer(address,uint256)
+		"d505accf": "ERC2612", // permit(address,address,uint256,uint256,uint8,bytes32,bytes32)
+		"6e553f65": "ERC4626", // deposit(uint256,address)
+		"d0e30db0": "WETH9",   // deposit()
+		"2e1a7d4d": "WETH9",   // withdraw(uint256)
+		"42842e0e": "ERC721",  // safeTransferFrom(address,address,uint256)
+		"01ffc9a7": "ERC165",  // supportsInterface(bytes4)
+	}
+	for id, standard := range expected {
+		std, ok := contractCache.stdMethods[id]
+		require.True(t, ok, "standard method %s should be registered", id)
+		assert.Equal(t, standard, std.Standard, "standard of method %s %s", id, std.Name)
+	}
+
+	// ERC20 methods keep the param names of the ERC777 ABI
+	std := contractCache.stdMethods["23b872dd"] // transferFrom(address,address,uint256)
+	assert.Equal(t, "ERC20", std.Standard, "standard of transferFrom")
+	assert.Equal(t, "holder,recipient,amount", paramNames(std.Method), "param names of transferFrom")
+	std = contractCache.stdMethods["a9059cbb"] // transfer(address,uint256)
+	assert.Equal(t, "recipient,amount", paramNames(std.Method), "param names of transfer")
+}
+
+func TestSharedStdMethod(t *testing.T) {
+	// approve(address,uint256) is defined by both ERC20 and ERC721
+	input, err := hex.DecodeString("095ea7b3000000000000000000000000dac17f958d2ee523a2206206994597c13d831ec70000000000000000000000000000000000000000000000000000000000000064")
+	require.NoError(t, err, "input data should be valid hex")
+
+	nft := "0x0000000000000000000000000000000000000005"
+	unknown := "0x0000000000000000000000000000000000000006"
+	contractCache.Lock()
+	contractCache.contracts[nft] = &common.Contract{Address: nft, AddressType: common.ContractAddress, TokenStandard: "ERC721"}
+	contractCache.contracts[unknown] = &common.Contract{Address: unknown, AddressType: common.ContractAddress}
+	contractCache.Unlock()
+	t.Cleanup(func() {
+		contractCache.Lock()
+		delete(contractCache.contracts, nft)
+		delete(contractCache.contracts, unknown)
+		contractCache.Unlock()
+	})
+
+	dec, err := DecodeTransactionInput(input, nft, 0, -1)
+	require.NoError(t, err, "decode approve of NFT should not throw error")
+	require.NotNil(t, dec, "approve of NFT should be decoded")
+	assert.Equal(t, "ERC721", dec.Standard, "approve of NFT should be decoded by ERC721 method")
+	assert.Equal(t, "tokenId", dec.Params[1].Name, "param of NFT approve should be tokenId")
+
+	dec, err = DecodeTransactionInput(input, unknown, 0, -1)
+	require.NoError(t, err, "decode approve of unknown token should not throw error")
+	require.NotNil(t, dec, "approve of unknown token should be decoded")
+	assert.Empty(t, dec.Standard, "approve of unknown token standard should not be labeled")
+}
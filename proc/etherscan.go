@@ -6,68 +6,216 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
 )
 
-type etherscan struct {
+// ErrBudgetExceeded is returned when all etherscan API keys have used up their daily call budget
+var ErrBudgetExceeded = errors.New("Etherscan daily call budget exceeded")
+
+// an etherscan API key with its own rate limiter and daily quota
+type apiKey struct {
 	sync.Mutex
-	apiKey   string // etherscan API key
+	key      string // etherscan API key
 	delay    int    // delay of consecutive etherscan API invocation in ms
 	lastTime int64  // Unix millis of last etherscan API invocation
+	callDate int64  // UTC date of the calls counted in calls
+	calls    int    // number of calls made on callDate
+}
+
+// pool of etherscan API keys used in round-robin
+type etherscan struct {
+	sync.Mutex
+	keys       []*apiKey
+	next       int // index of the key for next call
+	dailyLimit int // max calls per key per UTC day; 0 for unlimited
 }
 
 // singleton
 var api *etherscan
 
 func ConfigEtherscan(apiKey string, delay int) {
-	api = &etherscan{apiKey: apiKey}
-	if delay > 0 {
-		api.delay = delay
+	ConfigEtherscanKeys([]string{apiKey}, delay, 0)
+}
+
+// configure a pool of etherscan API keys, each key is rate limited by the delay in ms,
+// and used for no more than dailyLimit calls per UTC day (no limit if dailyLimit <= 0)
+func ConfigEtherscanKeys(apiKeys []string, delay, dailyLimit int) {
+	pool := &etherscan{}
+	if dailyLimit > 0 {
+		pool.dailyLimit = dailyLimit
 	}
+	for _, k := range apiKeys {
+		// keys may be separated by comma and spaces
+		if k = strings.TrimSpace(k); len(k) == 0 {
+			continue
+		}
+		key := &apiKey{key: k}
+		if delay > 0 {
+			key.delay = delay
+		}
+		pool.keys = append(pool.keys, key)
+	}
+	api = pool
 }
 
-// calls etherscan to fetch contract ABI - control the delay of calls so the rate is no more than 5 per second
-func FetchABI(address string, timeout int) (string, error) {
-	if api == nil || len(api.apiKey) == 0 {
-		// panic if API key is not configured
-		glog.Fatalln("Etherscan APIkey is not configured.  Must first call ConfigEtherscan(apiKey, delay)")
+// return the next API key that has not used up its daily budget, and count the call against the key
+func (c *etherscan) nextKey() (*apiKey, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	today := common.RoundToUTCDate(0)
+	for i := 0; i < len(c.keys); i++ {
+		key := c.keys[(c.next+i)%len(c.keys)]
+		if key.callDate != today {
+			// reset quota for a new day
+			key.callDate = today
+			key.calls = 0
+		}
+		if c.dailyLimit > 0 && key.calls >= c.dailyLimit {
+			continue
+		}
+		key.calls++
+		c.next = (c.next + i + 1) % len(c.keys)
+		return key, nil
 	}
+	return nil, ErrBudgetExceeded
+}
+
+// return the time when daily budget of API keys is reset, i.e., the next UTC midnight
+func EtherscanBudgetReset() time.Time {
+	return time.Unix(common.RoundToUTCDate(0), 0).Add(24 * time.Hour)
+}
 
-	// make 1 etherscan call at a time to limit the rate of API calls
+// return number of calls made today and total daily budget of all keys; budget is 0 if unlimited
+func EtherscanUsage() (int, int) {
+	if api == nil {
+		return 0, 0
+	}
 	api.Lock()
 	defer api.Unlock()
 
-	// fmt.Printf("Get ABI @ %d: %s\n", time.Now().UnixNano()/1000000, address)
-	if api.delay > 0 {
+	today := common.RoundToUTCDate(0)
+	calls := 0
+	for _, k := range api.keys {
+		if k.callDate == today {
+			calls += k.calls
+		}
+	}
+	return calls, api.dailyLimit * len(api.keys)
+}
+
+// wait till the key is ready for the next call, so the call rate of the key is no more than 1 per delay
+// must be called while holding the lock of the key
+func (k *apiKey) throttle() {
+	if k.delay > 0 {
 		// control etherscan call rate
-		delay := int64(api.delay) - (int64(time.Now().UnixNano()/1000000) - api.lastTime)
+		delay := int64(k.delay) - (int64(time.Now().UnixNano()/1000000) - k.lastTime)
 		if delay > 0 {
 			if glog.V(2) {
 				glog.Infof("Sleep %d ms", delay)
 			}
-			// fmt.Printf("Sleep %d ms\n", delay)
 			time.Sleep(time.Duration(delay) * time.Millisecond)
 		}
-		api.lastTime = int64(time.Now().UnixNano() / 1000000)
+		k.lastTime = int64(time.Now().UnixNano() / 1000000)
+	}
+}
+
+// invoke etherscan API using the next available key.
+// returns ErrBudgetExceeded if all keys have used up the daily budget.
+func callEtherscan(module, action, address string, timeout int) (interface{}, error) {
+	if api == nil || len(api.keys) == 0 {
+		// panic if API key is not configured
+		glog.Fatalln("Etherscan APIkey is not configured.  Must first call ConfigEtherscan(apiKey, delay)")
+	}
+
+	key, err := api.nextKey()
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := api.httpGetABI(address, timeout)
+	// make 1 etherscan call at a time per key to limit the rate of API calls
+	key.Lock()
+	defer key.Unlock()
+
+	key.throttle()
+	return key.httpGet(module, action, address, timeout)
+}
+
+// calls etherscan to fetch contract ABI - control the delay of calls so the rate of each key is no more than 5 per second
+func FetchABI(address string, timeout int) (string, error) {
+	data, err := callEtherscan("contract", "getabi", address, timeout)
 	if err != nil {
 		return "", err
 	}
 	return data.(string), nil
 }
 
+// contract source metadata returned by etherscan getsourcecode
+type SourceCode struct {
+	ABI             string
+	ContractName    string
+	CompilerVersion string
+	Optimized       bool
+	OptimizerRuns   int
+	License         string
+	IsProxy         bool
+	Implementation  string
+}
+
+// calls etherscan to fetch contract ABI and source metadata in a single call.
+// ABI is blank if the contract source code is not verified.
+func FetchSourceCode(address string, timeout int) (*SourceCode, error) {
+	data, err := callEtherscan("contract", "getsourcecode", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	results, ok := data.([]interface{})
+	if !ok || len(results) == 0 {
+		return nil, errors.Errorf("Etherscan getsourcecode returned invalid result for %s: %v", address, data)
+	}
+	src, ok := results[0].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("Etherscan getsourcecode returned invalid result for %s: %v", address, results[0])
+	}
+	getString := func(key string) string {
+		if v, ok := src[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	result := &SourceCode{
+		ContractName:    getString("ContractName"),
+		CompilerVersion: getString("CompilerVersion"),
+		Optimized:       getString("OptimizationUsed") == "1",
+		License:         getString("LicenseType"),
+		IsProxy:         getString("Proxy") == "1",
+		Implementation:  strings.ToLower(getString("Implementation")),
+	}
+	if runs, err := strconv.Atoi(getString("Runs")); err == nil {
+		result.OptimizerRuns = runs
+	}
+	if abi := getString("ABI"); strings.HasPrefix(abi, "[") {
+		// etherscan returns 'Contract source code not verified' if ABI is not available
+		result.ABI = abi
+	}
+	return result, nil
+}
+
 // Note: web3.etherscan.Query does not consistently return on consecutive calls, so use my own HTTP calls to etherscan
-func (c *etherscan) httpGetABI(address string, timeout int) (interface{}, error) {
+func (k *apiKey) httpGet(module, action, address string, timeout int) (interface{}, error) {
 	if timeout <= 0 {
 		// default time out to 10 second
 		timeout = 5
 	}
-	url := fmt.Sprintf("https://api.etherscan.io/api?apikey=%s&module=contract&action=getabi&address=%s", c.apiKey, address)
+	url := fmt.Sprintf("https://api.etherscan.io/api?apikey=%s&module=%s&action=%s&address=%s", k.key, module, action, address)
 
 	// We have to setup the transport timeout, otherwise, retry would not work after connection failure
 	var netTransport = &http.Transport{
@@ -29,3 +29,39 @@ func TestFetchABI(t *testing.T) {
 		assert.Equal(t, expected[i][1], len(ab.Events), "ABI event count does not match for contract: %s", addr)
 	}
 }
+
+func TestFetchSourceCode(t *testing.T) {
+	// USDC is a verified proxy contract
+	addr := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	src, err := FetchSourceCode(addr, 0)
+	require.NoError(t, err, "Error fetching source code from Etherscan: %s", addr)
+	assert.Equal(t, "FiatTokenProxy", src.ContractName, "contract name does not match for contract: %s", addr)
+	assert.True(t, src.IsProxy, "contract %s should be a proxy", addr)
+	assert.NotEmpty(t, src.Implementation, "proxy contract %s should have implementation address", addr)
+	assert.NotEmpty(t, src.CompilerVersion, "compiler version should not be empty for contract: %s", addr)
+	assert.NotEmpty(t, src.ABI, "ABI should not be empty for verified contract: %s", addr)
+}
+
+func TestEtherscanKeyRotation(t *testing.T) {
+	saved := api
+	defer func() { api = saved }()
+
+	ConfigEtherscanKeys([]string{"key1", " ", " key2"}, 0, 2)
+	require.Equal(t, 2, len(api.keys), "blank API key should be ignored")
+	assert.Equal(t, "key2", api.keys[1].key, "API key should be trimmed")
+
+	var used []string
+	for i := 0; i < 4; i++ {
+		key, err := api.nextKey()
+		require.NoError(t, err, "key should be available within daily budget")
+		used = append(used, key.key)
+	}
+	assert.Equal(t, []string{"key1", "key2", "key1", "key2"}, used, "keys should be used in round-robin")
+
+	_, err := api.nextKey()
+	assert.ErrorIs(t, err, ErrBudgetExceeded, "should return error when daily budget is exceeded")
+
+	calls, budget := EtherscanUsage()
+	assert.Equal(t, 4, calls, "calls of today should be counted")
+	assert.Equal(t, 4, budget, "daily budget should be total of all keys")
+}
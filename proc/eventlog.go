@@ -25,6 +25,9 @@ func DecodeEventLog(wlog *web3.Log, blockTime int64) (*common.EventLog, error) {
 		Data:        wlog.Data,
 		BlockTime:   blockTime,
 	}
+	for _, topic := range wlog.Topics {
+		result.Topics = append(result.Topics, topic.String())
+	}
 	// decode only if event topics exist
 	if len(wlog.Topics) < 1 {
 		glog.Warningf("Event log %d: %s No topics for contract %s", wlog.LogIndex, wlog.TransactionHash.String(), result.Address)
@@ -39,7 +42,11 @@ func DecodeEventLog(wlog *web3.Log, blockTime int64) (*common.EventLog, error) {
 	if data != nil {
 		// data decoded successfully
 		result.Event = data.Name
+		result.Standard = data.Standard
 		result.Params = data.Params
+
+		// normalize token amount by decimals of the cached token contract
+		setTokenAmount(result)
 	} else {
 		// failed to decode event data
 		result.Event = "UNKNOWN"
@@ -0,0 +1,173 @@
+package proc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/redshift"
+	"github.com/pkg/errors"
+	web3 "github.com/umbracle/ethgo"
+)
+
+// template ABI of child contracts created by a factory contract.
+// ABI of the child is set by (1) inline ABI; (2) ABI of a Template contract; or (3) ABI of the contract in TemplateParam of the event
+type FactoryTemplate struct {
+	Factory       string // address of the factory contract
+	Event         string // name of the event emitted when a child contract is created, e.g., PairCreated
+	ChildParam    string // name of the event param for the child contract address, e.g., pair
+	ABI           string // inline template ABI of child contracts
+	Template      string // address of a verified contract whose ABI is used as the template
+	TemplateParam string // name of the event param for the address of a verified template contract, e.g., singleton
+}
+
+// factory templates keyed by factory address and event name
+var factoryTemplates = make(map[string]*FactoryTemplate)
+
+func init() {
+	for _, t := range []*FactoryTemplate{
+		{
+			// Uniswap V2 factory, using ABI of USDC-WETH pair
+			Factory:    "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f",
+			Event:      "PairCreated",
+			ChildParam: "pair",
+			Template:   "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc",
+		},
+		{
+			// Uniswap V3 factory, using ABI of USDC-WETH 0.05% pool
+			Factory:    "0x1f98431c8ad98523631ae4a59f267346ea31f984",
+			Event:      "PoolCreated",
+			ChildParam: "pool",
+			Template:   "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+		},
+		{
+			// Gnosis Safe proxy factory 1.3.0, using ABI of the singleton in the event
+			Factory:       "0xa6b71e26c5e0845f74c812102ca7114b6a896ab2",
+			Event:         "ProxyCreation",
+			ChildParam:    "proxy",
+			TemplateParam: "singleton",
+		},
+		{
+			// Gnosis Safe proxy factory 1.1.1, using ABI of the master copy 1.1.1
+			Factory:    "0x76e2cfc1f5fa8f6a5b3fc4c8f4788f0116861f9b",
+			Event:      "ProxyCreation",
+			ChildParam: "proxy",
+			Template:   "0x34cfac646f301356faa8b21e94227e3583fe3f5f",
+		},
+	} {
+		RegisterFactoryTemplate(t)
+	}
+}
+
+func factoryKey(factory, event string) string {
+	return strings.ToLower(factory) + "/" + event
+}
+
+// add or replace the template for a factory event
+func RegisterFactoryTemplate(t *FactoryTemplate) error {
+	if len(t.Factory) == 0 || len(t.Event) == 0 || len(t.ChildParam) == 0 {
+		return errors.Errorf("Factory template must specify Factory, Event and ChildParam: %+v", t)
+	}
+	if len(t.ABI) == 0 && len(t.Template) == 0 && len(t.TemplateParam) == 0 {
+		return errors.Errorf("Factory template must specify ABI, Template or TemplateParam: %+v", t)
+	}
+	t.Factory = strings.ToLower(t.Factory)
+	t.Template = strings.ToLower(t.Template)
+	factoryTemplates[factoryKey(t.Factory, t.Event)] = t
+	return nil
+}
+
+// read factory templates from a JSON file of an array of FactoryTemplate, and add them to the default templates
+func LoadFactoryTemplates(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read factory template file %s", file)
+	}
+	var templates []*FactoryTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return errors.Wrapf(err, "Failed to parse factory template file %s", file)
+	}
+	for _, t := range templates {
+		if err := RegisterFactoryTemplate(t); err != nil {
+			return err
+		}
+	}
+	glog.Infof("Loaded %d factory templates from %s", len(templates), file)
+	return nil
+}
+
+// return address value of a named param, or blank if not found
+func addressParam(params []*common.NamedValue, name string) string {
+	for _, p := range params {
+		if p.Name == name {
+			if addr, ok := p.Value.(web3.Address); ok {
+				return strings.ToLower(addr.String())
+			}
+		}
+	}
+	return ""
+}
+
+// register child contract with the template ABI if the decoded event is a creation event of a known factory,
+// so the ABI of the child contract does not have to be fetched from etherscan.
+// returns fatal error if failed to connect to etherscan or database.
+func registerFactoryChild(factory string, dec *DecodedData, blockTime int64) error {
+	t, ok := factoryTemplates[factoryKey(factory, dec.Name)]
+	if !ok {
+		return nil
+	}
+	child := addressParam(dec.Params, t.ChildParam)
+	if len(child) == 0 {
+		glog.Warningf("Factory %s event %s does not contain child address %s", factory, dec.Name, t.ChildParam)
+		return nil
+	}
+
+	contractCache.Lock()
+	defer contractCache.Unlock()
+
+	if _, ok := contractCache.contracts[child]; ok {
+		// child contract is already known
+		return nil
+	}
+	if known, err := redshift.QueryContract(child); known != nil && err == nil {
+		// child contract is already stored
+		contractCache.contracts[child] = known
+		if parseABI(known) == nil {
+			cacheCodeHash(known)
+		}
+		return nil
+	}
+
+	contract := &common.Contract{
+		Address:       child,
+		AddressType:   common.ContractAddress,
+		LastEventDate: common.RoundToUTCDate(blockTime),
+		ABI:           t.ABI,
+	}
+	if len(contract.ABI) == 0 {
+		template := t.Template
+		if len(t.TemplateParam) > 0 {
+			template = addressParam(dec.Params, t.TemplateParam)
+		}
+		if len(template) == 0 {
+			glog.Warningf("Factory %s event %s does not contain template address %s", factory, dec.Name, t.TemplateParam)
+			return nil
+		}
+		known, err := findContract(template, 0, blockTime, false)
+		if err != nil {
+			return err
+		}
+		if known == nil || len(known.ABI) == 0 {
+			// template ABI is not available, so fetch ABI of the child when it is used
+			return nil
+		}
+		copySourceCode(contract, known)
+	}
+	if glog.V(1) {
+		glog.Infof("Register child contract %s created by factory %s event %s", child, factory, dec.Name)
+	}
+	_, err := completeContract(contract, contract.LastEventDate)
+	return err
+}
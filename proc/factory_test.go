@@ -0,0 +1,40 @@
+package proc
+
+// Run all unit test: `go test -v`
+
+import (
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/stretchr/testify/assert"
+	web3 "github.com/umbracle/ethgo"
+)
+
+func TestFactoryTemplate(t *testing.T) {
+	err := RegisterFactoryTemplate(&FactoryTemplate{
+		Factory:    "0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac",
+		Event:      "PairCreated",
+		ChildParam: "pair",
+	})
+	assert.Error(t, err, "template without ABI source should be rejected")
+
+	err = RegisterFactoryTemplate(&FactoryTemplate{
+		Factory:    "0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac",
+		Event:      "PairCreated",
+		ChildParam: "pair",
+		Template:   "0x397FF1542f962076d0BFE58eA045FfA2d347ACa0",
+	})
+	assert.NoError(t, err, "register factory template should not throw exception")
+	key := factoryKey("0xc0aee478e3658e2610c5f7a4a2e1777ce9e4f2ac", "PairCreated")
+	t.Cleanup(func() { delete(factoryTemplates, key) })
+	tmpl, ok := factoryTemplates[key]
+	assert.True(t, ok, "factory template should be keyed by lowercase address")
+	assert.Equal(t, "0x397ff1542f962076d0bfe58ea045ffa2d347aca0", tmpl.Template, "template address should be lowercase")
+
+	params := []*common.NamedValue{
+		{Name: "token0", Value: web3.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")},
+		{Name: "pair", Value: web3.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")},
+	}
+	assert.Equal(t, "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc", addressParam(params, "pair"), "should return child address")
+	assert.Empty(t, addressParam(params, "pool"), "should return blank for missing param")
+}
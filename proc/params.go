@@ -0,0 +1,84 @@
+package proc
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/umbracle/ethgo/abi"
+)
+
+// max number of params of a method or event, so sequence of params fits in Int8 of ClickHouse
+const maxParams = 128
+
+var flattenParams bool
+
+// if flag is true, components of tuple and array params are appended to decoded params with dotted names, e.g., order.maker, path[0]
+func SetFlattenParams(flag bool) {
+	flattenParams = flag
+}
+
+// return decoded params in order of the ABI arguments.
+// tuple and array params are kept as is, and serialized as JSON by database stores.
+// if flattening is enabled, their components are appended after all top-level params,
+// so that the sequence of top-level params does not change.
+func namedParams(args *abi.Type, values map[string]interface{}) []*common.NamedValue {
+	params := []*common.NamedValue{}
+	for _, elem := range args.TupleElems() {
+		params = append(params, &common.NamedValue{
+			Name:  elem.Name,
+			Kind:  elem.Elem.Kind(),
+			Value: values[elem.Name],
+		})
+	}
+	if !flattenParams {
+		return params
+	}
+	for _, elem := range args.TupleElems() {
+		params = appendComponents(params, elem.Name, elem.Elem, values[elem.Name])
+	}
+	return params
+}
+
+// append leaf components of a tuple or array value recursively, and ignore other types
+func appendComponents(params []*common.NamedValue, name string, typ *abi.Type, value interface{}) []*common.NamedValue {
+	switch typ.Kind() {
+	case abi.KindTuple:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return params
+		}
+		for _, elem := range typ.TupleElems() {
+			params = appendParam(params, name+"."+elem.Name, elem.Elem, m[elem.Name])
+		}
+	case abi.KindArray, abi.KindSlice:
+		ref := reflect.ValueOf(value)
+		if ref.Kind() != reflect.Array && ref.Kind() != reflect.Slice {
+			return params
+		}
+		for i := 0; i < ref.Len(); i++ {
+			params = appendParam(params, name+"["+strconv.Itoa(i)+"]", typ.Elem(), ref.Index(i).Interface())
+		}
+	}
+	return params
+}
+
+// append a component value if it is not a tuple or array, or its leaf components otherwise
+func appendParam(params []*common.NamedValue, name string, typ *abi.Type, value interface{}) []*common.NamedValue {
+	switch typ.Kind() {
+	case abi.KindTuple, abi.KindArray, abi.KindSlice:
+		return appendComponents(params, name, typ, value)
+	}
+	if len(params) >= maxParams {
+		if glog.V(1) {
+			glog.Infof("Skip flattened param %s beyond %d params", name, maxParams)
+		}
+		return params
+	}
+	return append(params, &common.NamedValue{
+		Name:  name,
+		Kind:  typ.Kind(),
+		Value: value,
+	})
+}
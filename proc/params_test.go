@@ -0,0 +1,37 @@
+package proc
+
+// Run all unit test: `go test -v`
+
+import (
+	"testing"
+
+	"github.com/open-dovetail/eth-track/contract/standard/multicall"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+)
+
+func TestFlattenParams(t *testing.T) {
+	target := web3.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	calls := []map[string]interface{}{
+		{"target": target, "allowFailure": true, "callData": []byte{0x01}},
+		{"target": target, "allowFailure": false, "callData": []byte{0x02}},
+	}
+	method := multicall.Multicall3Abi().Methods["aggregate3"]
+	input, err := method.Encode([]interface{}{calls})
+	require.NoError(t, err, "encode aggregate3 should not throw error")
+
+	SetFlattenParams(true)
+	defer SetFlattenParams(false)
+	dec, err := DecodeTransactionInput(input, "0x0000000000000000000000000000000000000003", 0, -1)
+	require.NoError(t, err, "decode aggregate3 should not throw error")
+	require.NotNil(t, dec, "aggregate3 should be decoded")
+	require.Equal(t, 7, len(dec.Params), "aggregate3 should contain calls and 6 flattened params")
+
+	assert.Equal(t, "calls", dec.Params[0].Name, "top-level param should keep its sequence")
+	assert.Equal(t, "calls[0].target", dec.Params[1].Name, "first flattened param")
+	assert.Equal(t, target, dec.Params[1].Value, "value of flattened address")
+	assert.Equal(t, "calls[0].allowFailure", dec.Params[2].Name, "flattened params should follow tuple components")
+	assert.Equal(t, "calls[1].callData", dec.Params[6].Name, "last flattened param")
+	assert.Equal(t, []byte{0x02}, dec.Params[6].Value, "value of flattened bytes")
+}
@@ -0,0 +1,378 @@
+package proc
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc165"
+	"github.com/open-dovetail/eth-track/contract/standard/erc20"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4626"
+	"github.com/open-dovetail/eth-track/redshift"
+	"github.com/pkg/errors"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+	econ "github.com/umbracle/ethgo/contract"
+)
+
+// ERC165 interface ID of a token standard
+type tokenInterface struct {
+	id       [4]byte
+	standard string
+}
+
+var (
+	erc165ID  = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	invalidID = [4]byte{0xff, 0xff, 0xff, 0xff}
+
+	// token standards detected by supportsInterface, in order of precedence
+	tokenInterfaces = []*tokenInterface{
+		{id: [4]byte{0xd9, 0xb6, 0x7a, 0x26}, standard: "ERC1155"},
+		{id: [4]byte{0x80, 0xac, 0x58, 0xcd}, standard: "ERC721"},
+		{id: interfaceID(erc4626.ERC4626Abi()), standard: "ERC4626"},
+	}
+)
+
+// return ERC165 interface ID, i.e., XOR of all method selectors of an ABI
+func interfaceID(a *abi.ABI) [4]byte {
+	var id [4]byte
+	for _, mth := range a.Methods {
+		sel := mth.ID()
+		for i := range id {
+			id[i] ^= sel[i]
+		}
+	}
+	return id
+}
+
+// return token standard of a contract by checking ERC165 supportsInterface,
+// or by probing ERC4626 asset() if the contract passed the ERC20 probe of decimals and totalSupply.
+// returns blank if the contract is not a recognized token.
+func detectTokenStandard(address string, isERC20 bool) string {
+	client := GetEthereumClient()
+	if client == nil {
+		return ""
+	}
+	addr := web3.HexToAddress(address)
+	opt := econ.WithJsonRPC(client.Eth())
+
+	introspect := erc165.NewERC165(addr, opt)
+	if supportsInterface(introspect, erc165ID) && !supportsInterface(introspect, invalidID) {
+		for _, t := range tokenInterfaces {
+			if supportsInterface(introspect, t.id) {
+				return t.standard
+			}
+		}
+	}
+	if !isERC20 {
+		return ""
+	}
+	// most ERC4626 vaults do not implement ERC165
+	if _, err := erc4626.NewERC4626(addr, opt).Asset(); err == nil {
+		return "ERC4626"
+	}
+	return "ERC20"
+}
+
+// return true if a contract reports that it supports the specified ERC165 interface
+func supportsInterface(introspect *erc165.ERC165, id [4]byte) bool {
+	ok, err := introspect.SupportsInterface(id)
+	if err != nil {
+		if glog.V(2) {
+			glog.Infof("supportsInterface %x failed: %v", id, err)
+		}
+		return false
+	}
+	return ok
+}
+
+var (
+	// events of fungible tokens that transfer, approve, mint or burn an amount of the token
+	tokenAmountEvents = map[string]bool{"Transfer": true, "Approval": true, "Mint": true, "Burn": true}
+	// names of the amount param of token events
+	tokenAmountParams = map[string]bool{"value": true, "amount": true, "wad": true, "_value": true, "_amount": true}
+)
+
+// set decimals-adjusted amount of an ERC20 Transfer, Approval, Mint or Burn event using decimals of the cached token contract.
+// amount is not set if the event is not emitted by a cached ERC20 or ERC4626 token, so standard events do not fetch contracts.
+func setTokenAmount(evt *common.EventLog) {
+	if !tokenAmountEvents[evt.Event] {
+		return
+	}
+	var value *big.Int
+	for _, p := range evt.Params {
+		if v, ok := p.Value.(*big.Int); ok && tokenAmountParams[p.Name] {
+			value = v
+			break
+		}
+	}
+	if value == nil {
+		return
+	}
+	contract := cachedContract(evt.Address)
+	if contract == nil {
+		return
+	}
+	if standard := backfillTokenStandard(contract); standard != "ERC20" && standard != "ERC4626" {
+		return
+	}
+	// decimals and event date may be updated concurrently by RefreshTokens
+	contractCache.Lock()
+	decimals := contract.Decimals
+	eventTime := common.RoundToUTCDate(evt.BlockTime)
+	updated := eventTime > contract.LastEventDate
+	if updated {
+		// track event date of active tokens for RefreshTokens
+		contract.LastEventDate = eventTime
+	}
+	snapshot := *contract
+	_, isNew := contractCache.created[contract.Address]
+	contractCache.Unlock()
+	evt.Amount = decimalsToFloat(value, decimals)
+
+	// db update is not made while holding the cache lock
+	if updated && !isNew {
+		if err := redshift.UpdateContract(&snapshot); err != nil {
+			glog.Warningf("Failed to update contract event time %s: %s", contract.Address, err.Error())
+		}
+	}
+}
+
+// classify token standard of a contract that was stored with a symbol before token standards were classified,
+// and update the contract in database. returns the token standard of the contract.
+func backfillTokenStandard(c *common.Contract) string {
+	contractCache.Lock()
+	standard := c.TokenStandard
+	check := len(standard) == 0 && len(c.Symbol) > 0 && !contractCache.backfilled[c.Address]
+	if check {
+		contractCache.backfilled[c.Address] = true
+	}
+	contractCache.Unlock()
+	if !check {
+		return standard
+	}
+
+	// contract calls are not made while holding the cache lock
+	meta := readTokenMetadata(c.Address)
+	if meta == nil {
+		return ""
+	}
+	if standard = detectTokenStandard(c.Address, meta.isERC20); len(standard) == 0 {
+		return ""
+	}
+	contractCache.Lock()
+	c.TokenStandard = standard
+	if meta.isERC20 {
+		c.Decimals = meta.decimals
+	}
+	snapshot := *c
+	_, isNew := contractCache.created[c.Address]
+	contractCache.Unlock()
+
+	if glog.V(1) {
+		glog.Infof("Backfill token standard %s of contract %s Symbol %s", standard, c.Address, snapshot.Symbol)
+	}
+	if !isNew {
+		if err := redshift.UpdateTokenMetadata(&snapshot); err != nil {
+			glog.Warningf("Failed to update token standard %s: %s", c.Address, err.Error())
+		}
+	}
+	return standard
+}
+
+// return float value of a token amount divided by 10^decimals
+func decimalsToFloat(value *big.Int, decimals uint8) float64 {
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(value), unit).Float64()
+	return f
+}
+
+// token metadata read from a contract
+type tokenMetadata struct {
+	name        string
+	symbol      string
+	decimals    uint8
+	totalSupply float64
+	exactSupply *big.Int
+	isERC20     bool // true if the contract responds to both decimals and totalSupply
+}
+
+// invoke a contract call, and convert panic of bad return data to error
+func safeCall(call func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("contract call panic: %v", r)
+		}
+	}()
+	return call()
+}
+
+// convert a bytes32 value returned by early tokens, e.g., MKR, to string, or blank if it is not valid UTF-8
+func bytes32ToString(b [32]byte) string {
+	s := bytes.TrimRight(b[:], "\x00")
+	if !utf8.Valid(s) {
+		return ""
+	}
+	return strings.TrimSpace(string(s))
+}
+
+// read ERC20 metadata of a token contract at the optional block, or the latest block if block is not specified.
+// name and symbol fall back to bytes32 if they are not returned as string.
+// calls that revert are ignored, and leave the corresponding value blank.
+func readTokenMetadata(address string, block ...web3.BlockNumber) *tokenMetadata {
+	client := GetEthereumClient()
+	if client == nil {
+		return nil
+	}
+	addr := web3.HexToAddress(address)
+	opt := econ.WithJsonRPC(client.Eth())
+	token := erc20.NewERC20(addr, opt)
+	result := &tokenMetadata{}
+
+	decErr := safeCall(func() (err error) {
+		result.decimals, err = token.Decimals(block...)
+		return err
+	})
+	supplyErr := safeCall(func() error {
+		totalSupply, err := token.TotalSupply(block...)
+		if err == nil {
+			result.totalSupply = common.BigIntToFloat(totalSupply)
+			result.exactSupply = totalSupply
+		}
+		return err
+	})
+	result.isERC20 = decErr == nil && supplyErr == nil
+
+	var token32 *erc20.ERC20Bytes32
+	if err := safeCall(func() (err error) {
+		result.name, err = token.Name(block...)
+		return err
+	}); err != nil {
+		token32 = erc20.NewERC20Bytes32(addr, opt)
+		safeCall(func() error {
+			name, err := token32.Name(block...)
+			if err == nil {
+				result.name = bytes32ToString(name)
+			}
+			return err
+		})
+	}
+	if err := safeCall(func() (err error) {
+		result.symbol, err = token.Symbol(block...)
+		return err
+	}); err != nil {
+		if token32 == nil {
+			token32 = erc20.NewERC20Bytes32(addr, opt)
+		}
+		safeCall(func() error {
+			symbol, err := token32.Symbol(block...)
+			if err == nil {
+				result.symbol = bytes32ToString(symbol)
+			}
+			return err
+		})
+	}
+	return result
+}
+
+// set ERC20 token properties, and return true if the contract responds to both decimals and totalSupply
+func updateERC20Properties(c *common.Contract) bool {
+	meta := readTokenMetadata(c.Address)
+	if meta == nil {
+		return false
+	}
+	c.Name = meta.name
+	c.Symbol = meta.symbol
+	c.Decimals = meta.decimals
+	c.TotalSupply = meta.totalSupply
+	c.ExactSupply = meta.exactSupply
+	return meta.isERC20
+}
+
+// re-read metadata and total supply of cached token contracts that have events since minEventTime,
+// update changed metadata in database, and record a supply snapshot of each token.
+// returns number of refreshed tokens, or error if failed to store the result in database.
+func RefreshTokens(minEventTime int64) (int, error) {
+	client := GetEthereumClient()
+	if client == nil {
+		return 0, errors.New("Ethereum client is not initialized")
+	}
+	blockNumber, err := client.Eth().BlockNumber()
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to get current block number")
+	}
+
+	// collect active tokens, so contract calls are not made while holding the cache lock
+	minEventDate := common.RoundToUTCDate(minEventTime)
+	var tokens []*common.Contract
+	contractCache.Lock()
+	for _, c := range contractCache.contracts {
+		// include contracts of a symbol that were stored before token standards were classified
+		if (len(c.TokenStandard) > 0 || len(c.Symbol) > 0) && c.LastEventDate >= minEventDate {
+			tokens = append(tokens, c)
+		}
+	}
+	contractCache.Unlock()
+
+	now := time.Now().Unix()
+	var supplies []*common.TokenSupply
+	for _, c := range tokens {
+		// read metadata and supply at the same block as the snapshot
+		meta := readTokenMetadata(c.Address, web3.BlockNumber(blockNumber))
+		if meta == nil || !meta.isERC20 {
+			// skip NFT and contracts that reverted on totalSupply
+			continue
+		}
+		supplies = append(supplies, &common.TokenSupply{
+			Address:      c.Address,
+			TotalSupply:  meta.totalSupply,
+			ExactSupply:  meta.exactSupply,
+			BlockNumber:  blockNumber,
+			SnapshotTime: now,
+		})
+
+		contractCache.Lock()
+		standard := c.TokenStandard
+		contractCache.Unlock()
+		if len(standard) == 0 {
+			// backfill token standard of a token stored before token standards were classified
+			standard = detectTokenStandard(c.Address, meta.isERC20)
+		}
+
+		// update shared contract under the cache lock, and store a copy, so decoders do not read partial updates
+		contractCache.Lock()
+		changed := c.Decimals != meta.decimals || c.TotalSupply != meta.totalSupply || c.TokenStandard != standard
+		c.TokenStandard = standard
+		c.Decimals = meta.decimals
+		c.TotalSupply = meta.totalSupply
+		c.ExactSupply = meta.exactSupply
+		// keep known name and symbol if the call failed this time
+		if len(meta.name) > 0 && c.Name != meta.name {
+			c.Name = meta.name
+			changed = true
+		}
+		if len(meta.symbol) > 0 && c.Symbol != meta.symbol {
+			c.Symbol = meta.symbol
+			changed = true
+		}
+		snapshot := *c
+		_, isNew := contractCache.created[c.Address]
+		contractCache.Unlock()
+
+		if changed && !isNew {
+			// new contracts are stored with the refreshed values in the next batch
+			if err := redshift.UpdateTokenMetadata(&snapshot); err != nil {
+				glog.Warningf("Failed to update token metadata %s: %s", c.Address, err.Error())
+			}
+		}
+	}
+	if err := redshift.InsertTokenSupplies(supplies); err != nil {
+		return 0, errors.Wrapf(err, "Failed to store %d token supplies", len(supplies))
+	}
+	glog.Infof("Refreshed %d of %d active tokens at block %d", len(supplies), len(tokens), blockNumber)
+	return len(supplies), nil
+}
@@ -0,0 +1,103 @@
+package proc
+
+// Run all unit test: `go test -v`
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc165"
+	"github.com/stretchr/testify/assert"
+	web3 "github.com/umbracle/ethgo"
+)
+
+func TestInterfaceID(t *testing.T) {
+	assert.Equal(t, erc165ID, interfaceID(erc165.ERC165Abi()), "interface ID of ERC165 should be the selector of supportsInterface")
+}
+
+func TestTokenStandard(t *testing.T) {
+	// DAI
+	dai := &common.Contract{Address: "0x6b175474e89094c44da98b954eedeac495271d0f"}
+	isERC20 := updateERC20Properties(dai)
+	assert.True(t, isERC20, "DAI should pass ERC20 probe")
+	assert.Equal(t, "ERC20", detectTokenStandard(dai.Address, isERC20), "DAI should be ERC20")
+
+	// BAYC
+	bayc := &common.Contract{Address: "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d"}
+	assert.Equal(t, "ERC721", detectTokenStandard(bayc.Address, updateERC20Properties(bayc)), "BAYC should be ERC721")
+}
+
+func TestBytes32ToString(t *testing.T) {
+	var b [32]byte
+	copy(b[:], "MKR")
+	assert.Equal(t, "MKR", bytes32ToString(b), "bytes32 symbol should be trimmed")
+
+	b[0] = 0xff
+	assert.Empty(t, bytes32ToString(b), "invalid UTF-8 should return blank")
+}
+
+func TestBytes32TokenMetadata(t *testing.T) {
+	// MKR returns name and symbol as bytes32
+	mkr := &common.Contract{Address: "0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2"}
+	assert.True(t, updateERC20Properties(mkr), "MKR should pass ERC20 probe")
+	assert.Equal(t, "MKR", mkr.Symbol, "MKR symbol should be decoded from bytes32")
+	assert.Equal(t, "Maker", mkr.Name, "MKR name should be decoded from bytes32")
+	assert.Equal(t, uint8(18), mkr.Decimals, "MKR decimals should be 18")
+}
+
+func TestDecimalsToFloat(t *testing.T) {
+	value, _ := new(big.Int).SetString("1234567890000000000000", 10)
+	assert.Equal(t, 1234.56789, decimalsToFloat(value, 18), "amount of 18 decimals")
+	assert.Equal(t, 12.5, decimalsToFloat(big.NewInt(12500000), 6), "amount of 6 decimals")
+	assert.Equal(t, float64(7), decimalsToFloat(big.NewInt(7), 0), "amount of no decimals")
+}
+
+func TestTokenAmount(t *testing.T) {
+	addr := "0x0000000000000000000000000000000000000008"
+	contractCache.Lock()
+	contractCache.contracts[addr] = &common.Contract{
+		Address:       addr,
+		AddressType:   common.ContractAddress,
+		Decimals:      6,
+		TokenStandard: "ERC20",
+		LastEventDate: common.RoundToUTCDate(-1),
+	}
+	contractCache.Unlock()
+	t.Cleanup(func() {
+		contractCache.Lock()
+		defer contractCache.Unlock()
+		delete(contractCache.contracts, addr)
+	})
+
+	evt := &common.EventLog{
+		Address:   addr,
+		Event:     "Transfer",
+		BlockTime: -1,
+		Params: []*common.NamedValue{
+			{Name: "from", Value: web3.ZeroAddress},
+			{Name: "to", Value: web3.ZeroAddress},
+			{Name: "value", Value: big.NewInt(2500000)},
+		},
+	}
+	setTokenAmount(evt)
+	assert.Equal(t, 2.5, evt.Amount, "transfer amount should be adjusted by decimals")
+
+	evt = &common.EventLog{
+		Address:   addr,
+		Event:     "Swap",
+		BlockTime: -1,
+		Params:    []*common.NamedValue{{Name: "amount", Value: big.NewInt(2500000)}},
+	}
+	setTokenAmount(evt)
+	assert.Zero(t, evt.Amount, "amount should not be set for other events")
+
+	evt = &common.EventLog{
+		Address:   "0x0000000000000000000000000000000000000009",
+		Event:     "Transfer",
+		BlockTime: -1,
+		Params:    []*common.NamedValue{{Name: "value", Value: big.NewInt(2500000)}},
+	}
+	setTokenAmount(evt)
+	assert.Zero(t, evt.Amount, "amount should not be set for uncached contract")
+}
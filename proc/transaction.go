@@ -49,7 +49,7 @@ func DecodeTransaction(tx *web3.Transaction, blockTime int64) (*common.Transacti
 		return result, nil
 	}
 
-	data, err := DecodeTransactionInput(tx.Input, result.To, blockTime)
+	data, err := DecodeTransactionInput(tx.Input, result.To, tx.BlockNumber, blockTime)
 	if err != nil {
 		// fatal error
 		return result, err
@@ -57,7 +57,20 @@ func DecodeTransaction(tx *web3.Transaction, blockTime int64) (*common.Transacti
 	if data != nil {
 		// data decoded successfully
 		result.Method = data.Name
+		result.Standard = data.Standard
 		result.Params = data.Params
+
+		// decode inner calls if it is a batch transaction
+		if result.Calls, err = DecodeInnerCalls(result.Hash, result.To, data, tx.BlockNumber, blockTime); err != nil {
+			// fatal error
+			return result, err
+		}
+
+		// decode user operations if it is an ERC-4337 bundle transaction
+		if result.UserOps, err = DecodeUserOperations(result.Hash, result.To, data, tx.BlockNumber, blockTime); err != nil {
+			// fatal error
+			return result, err
+		}
 	} else {
 		// failed to decode data
 		result.Method = "UNKNOWN"
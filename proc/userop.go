@@ -0,0 +1,170 @@
+package proc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4337"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+)
+
+// handleOps method IDs of EntryPoint v0.6 and v0.7
+var handleOpsMethods = make(map[string]bool)
+
+func init() {
+	for _, a := range []*abi.ABI{erc4337.EntryPointAbi(), erc4337.EntryPointV7Abi()} {
+		handleOpsMethods[hex.EncodeToString(a.Methods["handleOps"].ID())] = true
+	}
+}
+
+// return lower-case address of the first 20 bytes of initCode or paymasterAndData, or blank if data is too short
+func leadingAddress(data []byte) string {
+	if len(data) < 20 {
+		return ""
+	}
+	return strings.ToLower(web3.BytesToAddress(data[:20]).String())
+}
+
+// decode user operations of an EntryPoint handleOps transaction, and decode callData of each operation against the ABI of its sender account,
+// or the ABI of its implementation if the account is a proxy.
+// returns nil if the decoded method is not handleOps.
+// returns fatal error if failed to connect to etherscan or database
+func DecodeUserOperations(txHash, entryPoint string, dec *DecodedData, blockNumber uint64, blockTime int64) ([]*common.UserOperation, error) {
+	if dec == nil || !handleOpsMethods[dec.ID] {
+		return nil, nil
+	}
+	ops, ok := paramValue(dec, "ops").([]map[string]interface{})
+	if !ok {
+		glog.Warningf("Transaction %s handleOps contains no user operations", txHash)
+		return nil, nil
+	}
+
+	var result []*common.UserOperation
+	for i, op := range ops {
+		sender, _ := op["sender"].(web3.Address)
+		nonce, _ := op["nonce"].(*big.Int)
+		initCode, _ := op["initCode"].([]byte)
+		paymasterAndData, _ := op["paymasterAndData"].([]byte)
+		callData, _ := op["callData"].([]byte)
+		userOp := &common.UserOperation{
+			TxnHash:     txHash,
+			OpIndex:     i,
+			BlockNumber: blockNumber,
+			EntryPoint:  entryPoint,
+			Sender:      strings.ToLower(sender.String()),
+			Nonce:       nonce,
+			Factory:     leadingAddress(initCode),
+			Paymaster:   leadingAddress(paymasterAndData),
+			CallData:    callData,
+			BlockTime:   blockTime,
+		}
+		result = append(result, userOp)
+		if len(callData) < 4 {
+			// no call to the account, e.g., deploy account only
+			continue
+		}
+		account, err := accountABIAddress(userOp.Sender, hex.EncodeToString(callData[:4]), blockNumber, blockTime)
+		if err != nil {
+			// fatal error
+			return result, err
+		}
+		data, err := DecodeTransactionInput(callData, account, blockNumber, blockTime)
+		if err != nil {
+			// fatal error
+			return result, err
+		}
+		if data == nil {
+			userOp.Method = "UNKNOWN"
+			continue
+		}
+		userOp.Method = data.Name
+		userOp.Standard = data.Standard
+		userOp.Params = data.Params
+		if glog.V(2) {
+			glog.Infof("User operation %s %d: %s Method %s", txHash, i, userOp.Sender, userOp.Method)
+		}
+	}
+	return result, nil
+}
+
+// return address of the contract whose ABI contains the method called by a smart account,
+// i.e., implementation of a proxy account, or target of an EIP-1167 minimal proxy account, or the account itself.
+// returns fatal error if failed to connect to etherscan or database
+func accountABIAddress(sender, methodID string, blockNumber uint64, blockTime int64) (string, error) {
+	if _, ok := contractCache.stdMethods[methodID]; ok && cachedContract(sender) == nil {
+		// standard method is decoded without fetching the account
+		return sender, nil
+	}
+	address := sender
+	// follow at most 3 levels, e.g., clone of a proxy of an implementation
+	for depth := 0; depth < 3; depth++ {
+		contract, err := getContract(address, blockNumber, blockTime)
+		if err != nil || contract == nil || contract.AddressType == common.EOAAddress {
+			return sender, err
+		}
+		if _, ok := contract.Methods[methodID]; ok {
+			return address, nil
+		}
+		next := contract.Implementation
+		if len(next) == 0 || next == address {
+			next = contract.CloneTarget
+		}
+		if len(next) == 0 || next == address {
+			break
+		}
+		if glog.V(2) {
+			glog.Infof("Decode method %s of account %s using ABI of %s", methodID, sender, next)
+		}
+		address = next
+	}
+	return sender, nil
+}
+
+// key to match a user operation with its UserOperationEvent
+func userOpKey(txHash, sender string, nonce *big.Int) string {
+	if nonce == nil {
+		nonce = big.NewInt(0)
+	}
+	return txHash + ":" + sender + ":" + nonce.String()
+}
+
+// set hash and execution result of user operations from UserOperationEvent logs emitted by EntryPoint in the same block
+func JoinUserOperationEvents(b *common.Block) {
+	userOps := make(map[string]*common.UserOperation)
+	for _, t := range b.Transactions {
+		for _, op := range t.UserOps {
+			userOps[userOpKey(op.TxnHash, op.Sender, op.Nonce)] = op
+		}
+	}
+	if len(userOps) == 0 {
+		return
+	}
+
+	for _, evt := range b.Logs {
+		if evt.Event != "UserOperationEvent" {
+			continue
+		}
+		values := make(map[string]interface{})
+		for _, p := range evt.Params {
+			values[p.Name] = p.Value
+		}
+		sender, _ := values["sender"].(web3.Address)
+		nonce, _ := values["nonce"].(*big.Int)
+		op, ok := userOps[userOpKey(evt.TxnHash, strings.ToLower(sender.String()), nonce)]
+		if !ok || op.EntryPoint != evt.Address {
+			continue
+		}
+		if hash, ok := values["userOpHash"].([32]byte); ok {
+			op.UserOpHash = web3.Hash(hash).String()
+		}
+		op.Success, _ = values["success"].(bool)
+		op.ActualGasCost, _ = values["actualGasCost"].(*big.Int)
+		if gasUsed, ok := values["actualGasUsed"].(*big.Int); ok && gasUsed.IsUint64() {
+			op.ActualGasUsed = gasUsed.Uint64()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package proc
+
+// Run all unit test: `go test -v`
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc20"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4337"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+)
+
+func TestDecodeUserOperations(t *testing.T) {
+	to := web3.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	transfer, err := erc20.ERC20Abi().Methods["transfer"].Encode([]interface{}{to, big.NewInt(100)})
+	require.NoError(t, err, "encode transfer should not throw error")
+
+	sender := web3.HexToAddress("0x0000000000000000000000000000000000000005")
+	paymaster := web3.HexToAddress("0x0000000000000000000000000000000000000006")
+	op := map[string]interface{}{
+		"sender":               sender,
+		"nonce":                big.NewInt(7),
+		"initCode":             []byte{},
+		"callData":             transfer,
+		"callGasLimit":         big.NewInt(100000),
+		"verificationGasLimit": big.NewInt(100000),
+		"preVerificationGas":   big.NewInt(50000),
+		"maxFeePerGas":         big.NewInt(1000000000),
+		"maxPriorityFeePerGas": big.NewInt(1000000),
+		"paymasterAndData":     append(paymaster.Bytes(), 0x01, 0x02),
+		"signature":            []byte{0x01},
+	}
+	beneficiary := web3.HexToAddress("0x0000000000000000000000000000000000000007")
+	input, err := erc4337.EntryPointAbi().Methods["handleOps"].Encode([]interface{}{[]map[string]interface{}{op}, beneficiary})
+	require.NoError(t, err, "encode handleOps should not throw error")
+
+	entryPoint := "0x0000000000000000000000000000000000000004"
+	dec, err := DecodeTransactionInput(input, entryPoint, 0, -1)
+	require.NoError(t, err, "decode handleOps should not throw error")
+	require.NotNil(t, dec, "handleOps should be decoded")
+	assert.Equal(t, "ERC4337", dec.Standard, "handleOps should be decoded by standard method")
+
+	ops, err := DecodeUserOperations("0x01", entryPoint, dec, 0, -1)
+	require.NoError(t, err, "decode user operations should not throw error")
+	require.Equal(t, 1, len(ops), "handleOps should contain 1 user operation")
+	assert.Equal(t, "0x0000000000000000000000000000000000000005", ops[0].Sender, "sender of user operation")
+	assert.Equal(t, int64(7), ops[0].Nonce.Int64(), "nonce of user operation")
+	assert.Equal(t, "0x0000000000000000000000000000000000000006", ops[0].Paymaster, "paymaster of user operation")
+	assert.Empty(t, ops[0].Factory, "existing account should not have factory")
+	assert.Equal(t, "transfer", ops[0].Method, "callData should be decoded as transfer")
+	assert.Equal(t, "ERC20", ops[0].Standard, "callData should be decoded by standard method")
+
+	// join UserOperationEvent of the same transaction
+	b := &common.Block{
+		Transactions: map[string]*common.Transaction{"0x01": {Hash: "0x01", UserOps: ops}},
+		Logs: map[uint64]*common.EventLog{0: {
+			TxnHash: "0x01",
+			Address: entryPoint,
+			Event:   "UserOperationEvent",
+			Params: []*common.NamedValue{
+				{Name: "userOpHash", Value: [32]byte{0x0a}},
+				{Name: "sender", Value: sender},
+				{Name: "paymaster", Value: paymaster},
+				{Name: "nonce", Value: big.NewInt(7)},
+				{Name: "success", Value: true},
+				{Name: "actualGasCost", Value: big.NewInt(3000)},
+				{Name: "actualGasUsed", Value: big.NewInt(150000)},
+			},
+		}},
+	}
+	JoinUserOperationEvents(b)
+	assert.Equal(t, "0x0a00000000000000000000000000000000000000000000000000000000000000", ops[0].UserOpHash, "hash of user operation")
+	assert.True(t, ops[0].Success, "user operation should succeed")
+	assert.Equal(t, int64(3000), ops[0].ActualGasCost.Int64(), "actual gas cost of user operation")
+	assert.Equal(t, uint64(150000), ops[0].ActualGasUsed, "actual gas used by user operation")
+}
+
+func TestDecodeProxyAccountOperation(t *testing.T) {
+	// clone of a proxy account, whose implementation contains the execute method
+	clone := "0x000000000000000000000000000000000000000c"
+	proxy := "0x000000000000000000000000000000000000000a"
+	impl := "0x000000000000000000000000000000000000000b"
+	contracts := []*common.Contract{
+		{Address: clone, CloneTarget: proxy,
+			ABI: `[{"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`},
+		{Address: proxy, IsProxy: true, Implementation: impl,
+			ABI: `[{"inputs":[{"name":"newImplementation","type":"address"}],"name":"upgradeTo","outputs":[],"stateMutability":"nonpayable","type":"function"}]`},
+		{Address: impl,
+			ABI: `[{"inputs":[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],"name":"execute","outputs":[],"stateMutability":"nonpayable","type":"function"}]`},
+	}
+	contractCache.Lock()
+	for _, c := range contracts {
+		c.AddressType = common.ContractAddress
+		c.LastEventDate = common.RoundToUTCDate(-1)
+		require.NoError(t, parseABI(c), "contract ABI should be parsed")
+		contractCache.contracts[c.Address] = c
+	}
+	contractCache.Unlock()
+	t.Cleanup(func() {
+		contractCache.Lock()
+		defer contractCache.Unlock()
+		for _, c := range contracts {
+			delete(contractCache.contracts, c.Address)
+		}
+	})
+
+	dest := web3.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	callData, err := contracts[2].Methods["b61d27f6"].Encode([]interface{}{dest, big.NewInt(0), []byte{0x01}})
+	require.NoError(t, err, "encode execute should not throw error")
+	op := map[string]interface{}{
+		"sender":               web3.HexToAddress(clone),
+		"nonce":                big.NewInt(1),
+		"initCode":             []byte{},
+		"callData":             callData,
+		"callGasLimit":         big.NewInt(100000),
+		"verificationGasLimit": big.NewInt(100000),
+		"preVerificationGas":   big.NewInt(50000),
+		"maxFeePerGas":         big.NewInt(1000000000),
+		"maxPriorityFeePerGas": big.NewInt(1000000),
+		"paymasterAndData":     []byte{},
+		"signature":            []byte{0x01},
+	}
+	beneficiary := web3.HexToAddress("0x0000000000000000000000000000000000000007")
+	input, err := erc4337.EntryPointAbi().Methods["handleOps"].Encode([]interface{}{[]map[string]interface{}{op}, beneficiary})
+	require.NoError(t, err, "encode handleOps should not throw error")
+
+	entryPoint := "0x0000000000000000000000000000000000000004"
+	dec, err := DecodeTransactionInput(input, entryPoint, 0, -1)
+	require.NoError(t, err, "decode handleOps should not throw error")
+	ops, err := DecodeUserOperations("0x02", entryPoint, dec, 0, -1)
+	require.NoError(t, err, "decode user operations should not throw error")
+	require.Equal(t, 1, len(ops), "handleOps should contain 1 user operation")
+	assert.Equal(t, clone, ops[0].Sender, "sender of user operation should be the account")
+	assert.Equal(t, "execute", ops[0].Method, "callData should be decoded by ABI of the proxy implementation")
+	require.Equal(t, 3, len(ops[0].Params), "execute should have 3 params")
+	assert.Equal(t, "dest", ops[0].Params[0].Name, "param name should come from implementation ABI")
+}
@@ -3,6 +3,7 @@ package redshift
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"sync"
 	"time"
@@ -12,127 +13,198 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 )
 
-// AWS managed secret for redshift db user & password
+// AWS s3 bucket, or a bucket of s3-compatible object store, e.g., MinIO
 type s3Bucket struct {
 	sync.Mutex
-	name     string
-	region   string
-	profile  string
-	copyRole string
-	ctx      context.Context
-	client   *s3.Client
-	created  int64 // time when client was last created in seconds
+	name      string
+	region    string
+	profile   string
+	copyRole  string
+	endpoint  string // URL of s3-compatible object store, blank for AWS s3
+	pathStyle bool   // true to address bucket in URL path instead of host name
+	ctx       context.Context
+	client    *s3.Client
+	created   int64 // time when client was last created in seconds
 }
 
 var bucket *s3Bucket
 
-// initialize or return s3 bucket
+// initialize or return s3 bucket, which is also used as object store of staged files if no other store is set
 func GetS3Bucket(bucketName, profile, region, copyRole string) (*s3Bucket, error) {
+	return getS3Bucket(&s3Bucket{
+		name:     bucketName,
+		region:   region,
+		profile:  profile,
+		copyRole: copyRole,
+	})
+}
+
+// create a bucket of AWS s3, or of an s3-compatible object store at the endpoint URL, e.g., http://localhost:9000 for MinIO.
+// it is not the singleton bucket of staged files, so it is used to export files to a separate bucket.
+func NewS3Bucket(bucketName, endpoint, profile, region string, pathStyle bool) (ObjectStore, error) {
+	b := &s3Bucket{
+		name:      bucketName,
+		region:    region,
+		profile:   profile,
+		endpoint:  endpoint,
+		pathStyle: pathStyle,
+	}
+	if err := b.connect(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// return the singleton bucket if it is already initialized with the same bucket name and endpoint,
+// or return error if it is initialized with a different bucket.
+func getS3Bucket(b *s3Bucket) (*s3Bucket, error) {
 	if bucket != nil {
+		if bucket.name != b.name || bucket.endpoint != b.endpoint || bucket.pathStyle != b.pathStyle {
+			return nil, errors.Errorf("s3 bucket is already initialized as %s at endpoint '%s'", bucket.name, bucket.endpoint)
+		}
 		return bucket, nil
 	}
 
-	// S3 client with AWS profile, region, and default config/credential specified in ~/.aws
-	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(region),
-		config.WithSharedConfigProfile(profile))
-	if err != nil {
-		// Handle session creation error
-		glog.Errorf("Failed to get config for AWS region %s and profile %s: %s", region, profile, err.Error())
+	if err := b.connect(); err != nil {
 		return nil, err
 	}
-	bucket = &s3Bucket{
-		name:     bucketName,
-		region:   region,
-		profile:  profile,
-		copyRole: copyRole,
-		ctx:      ctx,
-		client:   s3.NewFromConfig(cfg),
-		created:  time.Now().Unix(),
+	bucket = b
+	if objectStore == nil {
+		objectStore = b
 	}
 	return bucket, nil
 }
 
+// create S3 client with AWS profile, region, and default config/credential specified in ~/.aws
+func (b *s3Bucket) connect() error {
+	b.ctx = context.Background()
+	cfg, err := config.LoadDefaultConfig(b.ctx,
+		config.WithRegion(b.region),
+		config.WithSharedConfigProfile(b.profile))
+	if err != nil {
+		// Handle session creation error
+		glog.Errorf("Failed to get config for AWS region %s and profile %s: %s", b.region, b.profile, err.Error())
+		return err
+	}
+	b.client = b.newClient(cfg)
+	b.created = time.Now().Unix()
+	return nil
+}
+
+// create s3 client, using custom endpoint and addressing style for s3-compatible object store
+func (b *s3Bucket) newClient(cfg aws.Config) *s3.Client {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if len(b.endpoint) > 0 {
+			o.EndpointResolver = s3.EndpointResolverFromURL(b.endpoint)
+		}
+		o.UsePathStyle = b.pathStyle
+	})
+}
+
 // refresh client before s3 credential expires, i.e., reset every 5 minutes
-func refreshClient() {
-	if time.Now().Unix() <= bucket.created+300 {
+func (b *s3Bucket) refreshClient() {
+	if time.Now().Unix() <= b.created+300 {
 		// do not refresh if it was created within the last 5 minutes
 		return
 	}
 
 	// get a lock so only one thread will reset the client
-	bucket.Lock()
-	defer bucket.Unlock()
+	b.Lock()
+	defer b.Unlock()
 
-	if time.Now().Unix() > bucket.created+300 {
+	if time.Now().Unix() > b.created+300 {
 		// refresh only if it was not already reset by another thread while waiting for the lock
-		cfg, err := config.LoadDefaultConfig(bucket.ctx,
-			config.WithRegion(bucket.region),
-			config.WithSharedConfigProfile(bucket.profile))
+		cfg, err := config.LoadDefaultConfig(b.ctx,
+			config.WithRegion(b.region),
+			config.WithSharedConfigProfile(b.profile))
 		if err == nil {
-			glog.Infof("Refresh s3 connection for bucket %s region %s profile %s", bucket.name, bucket.region, bucket.profile)
-			bucket.created = time.Now().Unix()
-			bucket.client = s3.NewFromConfig(cfg)
+			glog.Infof("Refresh s3 connection for bucket %s region %s profile %s", b.name, b.region, b.profile)
+			b.created = time.Now().Unix()
+			b.client = b.newClient(cfg)
 		}
 	}
 }
 
-func deleteS3File(name string) (*s3.DeleteObjectOutput, error) {
-	return bucket.client.DeleteObject(bucket.ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(bucket.name),
+// return s3 URL of an object in the bucket
+func (b *s3Bucket) URL(name string) string {
+	return fmt.Sprintf("s3://%s/%s", b.name, name)
+}
+
+func (b *s3Bucket) DeleteFile(name string) error {
+	_, err := b.client.DeleteObject(b.ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.name),
 		Key:    aws.String(name),
 	})
+	return err
 }
 
-func deleteS3Folder(name string) (*s3.DeleteObjectsOutput, error) {
+// delete all objects with the folder prefix, including objects beyond the first 1000 of a listing
+func (b *s3Bucket) DeleteFolder(name string) error {
 	prefix := name
 	if name[len(name)-1:] != "/" {
 		prefix += "/"
 	}
-	list, err := bucket.client.ListObjectsV2(bucket.ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucket.name),
+	pages := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.name),
 		Prefix: aws.String(prefix),
 	})
-	if err != nil {
-		return nil, err
-	}
-	if list.IsTruncated {
-		// returned max of 1000, some still remaining
-	}
-	var items []types.ObjectIdentifier
-	for _, v := range list.Contents {
-		items = append(items, types.ObjectIdentifier{Key: v.Key})
-	}
-	if len(items) == 0 {
-		return nil, nil
+	for pages.HasMorePages() {
+		list, err := pages.NextPage(b.ctx)
+		if err != nil {
+			return err
+		}
+		// a page contains max of 1000 objects, which can be deleted in one request
+		var items []types.ObjectIdentifier
+		for _, v := range list.Contents {
+			items = append(items, types.ObjectIdentifier{Key: v.Key})
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if _, err := b.client.DeleteObjects(b.ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(b.name),
+			Delete: &types.Delete{Objects: items},
+		}); err != nil {
+			return err
+		}
 	}
-	return bucket.client.DeleteObjects(bucket.ctx, &s3.DeleteObjectsInput{
-		Bucket: aws.String(bucket.name),
-		Delete: &types.Delete{Objects: items},
-	})
+	return nil
 }
 
-func writeS3File(name string, content []byte) (*s3.PutObjectOutput, error) {
-	refreshClient()
-	return bucket.client.PutObject(bucket.ctx, &s3.PutObjectInput{
-		Bucket:             aws.String(bucket.name),
+func (b *s3Bucket) WriteFile(name string, content []byte) error {
+	b.refreshClient()
+	_, err := b.client.PutObject(b.ctx, &s3.PutObjectInput{
+		Bucket:             aws.String(b.name),
 		Key:                aws.String(name),
 		Body:               bytes.NewReader(content),
 		ContentType:        aws.String("text/plain"),
 		ContentDisposition: aws.String("attachment"),
 	})
+	return err
+}
+
+func (b *s3Bucket) FileSize(name string) (int64, error) {
+	resp, err := b.client.HeadObject(b.ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.name),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.ContentLength, nil
 }
 
-func readS3File(name string) ([]byte, error) {
-	resp, err := bucket.client.GetObject(bucket.ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket.name),
+func (b *s3Bucket) ReadFile(name string) ([]byte, error) {
+	resp, err := b.client.GetObject(b.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.name),
 		Key:    aws.String(name),
 	})
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 	return ioutil.ReadAll(resp.Body)
 }
@@ -35,19 +35,31 @@ func TestWriteS3Files(t *testing.T) {
 	testFolder := "test"
 	testFile1 := testFolder + "/test1.csv"
 	testFile2 := testFolder + "/test2.csv"
-	out, err := writeS3File(testFile1, []byte(testData))
+	err = bucket.WriteFile(testFile1, []byte(testData))
+	assert.NoError(t, err, "write S3 file should not throw error")
+	err = bucket.WriteFile(testFile2, []byte(testData))
 	assert.NoError(t, err, "write S3 file should not throw error")
-	assert.NotNil(t, out, "output from write S3 file should not be nil")
-	out, err = writeS3File(testFile2, []byte(testData))
 
-	data, err := readS3File(testFile1)
+	data, err := bucket.ReadFile(testFile1)
 	assert.NoError(t, err, "read S3 file should not throw error")
 	assert.Equal(t, testData, string(data), "downloaded s3 file should match test data")
 
-	data, err = readS3File(testFile2)
+	data, err = bucket.ReadFile(testFile2)
 	assert.NoError(t, err, "read S3 file should not throw error")
 	assert.Equal(t, testData, string(data), "downloaded s3 file should match test data")
 
-	_, err = deleteS3Folder(testFolder)
+	err = bucket.DeleteFolder(testFolder)
 	assert.NoError(t, err, "delete S3 folder should not throw error")
 }
+
+func TestMismatchedS3Bucket(t *testing.T) {
+	saved := bucket
+	bucket = &s3Bucket{name: "dev-eth-track"}
+	t.Cleanup(func() { bucket = saved })
+
+	_, err := GetS3Bucket("other-eth-track", "default", "us-west-2", "")
+	assert.Error(t, err, "bucket of a different name should be rejected")
+	b, err := GetS3Bucket("dev-eth-track", "default", "us-west-2", "")
+	assert.NoError(t, err, "bucket of the same name should be returned")
+	assert.Equal(t, bucket, b, "should return the initialized bucket")
+}
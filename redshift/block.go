@@ -3,7 +3,6 @@ package redshift
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/golang/glog"
 	"github.com/open-dovetail/eth-track/common"
@@ -16,7 +15,7 @@ type copyFromBlocks struct {
 
 // column names for batch insert or copy
 func blockColumns() []string {
-	return []string{"Hash", "Number", "ParentHash", "Miner", "Difficulty", "GasLimit", "GasUsed", "BlockTime"}
+	return []string{"Hash", "Number", "ParentHash", "Miner", "Difficulty", "ExactDifficulty", "GasLimit", "GasUsed", "BlockTime"}
 }
 
 // implement pgx.CopyFromSource interface, return tuple of values in order of blockColumns()
@@ -28,6 +27,7 @@ func (c *copyFromBlocks) Values() ([]interface{}, error) {
 	v = append(v, common.HexToFixedString(block.ParentHash.String(), 64))
 	v = append(v, common.HexToFixedString(block.Miner, 40))
 	v = append(v, common.BigIntToFloat(block.Difficulty))
+	v = append(v, common.BigIntToString(block.Difficulty))
 	v = append(v, block.GasLimit)
 	v = append(v, block.GasUsed)
 	v = append(v, common.SecondsToDateTime(block.BlockTime))
@@ -56,13 +56,14 @@ func InsertBlock(block *common.Block) error {
 		return err
 	}
 	ctx := context.Background()
-	sql := "INSERT INTO eth.blocks (Hash, Number, ParentHash, Miner, Difficulty, GasLimit, GasUsed, BlockTime) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	sql := "INSERT INTO eth.blocks (Hash, Number, ParentHash, Miner, Difficulty, ExactDifficulty, GasLimit, GasUsed, BlockTime) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
 	if _, err := tx.Exec(ctx, sql,
 		common.HexToFixedString(block.Hash, 64),
 		block.Number,
 		common.HexToFixedString(block.ParentHash.String(), 64),
 		common.HexToFixedString(block.Miner, 40),
 		common.BigIntToFloat(block.Difficulty),
+		common.BigIntToString(block.Difficulty),
 		block.GasLimit,
 		block.GasUsed,
 		common.SecondsToDateTime(block.BlockTime),
@@ -115,33 +116,66 @@ func writeBlocksToS3(blocks map[string]*common.Block, s3Folder string) error {
 	}
 
 	var err error
-	var txCount, logCount int
+	var txCount, callCount, opCount, logCount, txParamCount, logParamCount int
 	if txCount, err = writeTransactionsToS3(blocks, s3Folder); err != nil {
 		return err
 	}
+	if callCount, err = writeInnerCallsToS3(blocks, s3Folder); err != nil {
+		return err
+	}
+	if opCount, err = writeUserOperationsToS3(blocks, s3Folder); err != nil {
+		return err
+	}
 	if logCount, err = writeEventLogsToS3(blocks, s3Folder); err != nil {
 		return err
 	}
 
+	if txParamCount, err = writeTransactionParamsToS3(blocks, s3Folder); err != nil {
+		return err
+	}
+	if logParamCount, err = writeLogParamsToS3(blocks, s3Folder); err != nil {
+		return err
+	}
+
 	// write blocks to s3
 	source := &copyFromBlocks{idx: -1}
 	for _, v := range blocks {
 		source.rows = append(source.rows, v)
 	}
-	var data []byte
-	if data, err = composeCSVData(source); err != nil {
-		return err
-	}
+	glog.Infof("Write data to s3: %d blocks, %d transactions, %d inner calls, %d user operations, %d event logs, %d transaction params, %d log params",
+		len(blocks), txCount, callCount, opCount, logCount, txParamCount, logParamCount)
+	return writeStagedRows(s3Folder, blockTable("blocks"), source)
+}
 
-	//fmt.Println("Write blocks to s3:", string(data))
-	glog.Infof("Write data to s3: %d blocks, %d transactions, %d event logs", len(blocks), txCount, logCount)
-	s3Filename := fmt.Sprintf("%s/blocks.csv", s3Folder)
-	_, err = writeS3File(s3Filename, data)
+// tables of blocks/transactions/events, merged from staging tables in order.
+// transaction params are deleted by transactions of staged blocks, so it must precede transactions.
+func blockTables() []*stagedTable {
+	byBlock := "BlockNumber IN (SELECT Number FROM stage_blocks)"
+	return []*stagedTable{
+		{name: "transaction_params", columns: transactionParamColumns(), csvFile: "transaction_params.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ",
+			deleteBy: "TxnHash IN (SELECT Hash FROM eth.transactions WHERE " + byBlock + ")"},
+		{name: "transactions", columns: transactionColumns(), csvFile: "transactions.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ", deleteBy: byBlock},
+		{name: "calls", columns: innerCallColumns(), csvFile: "calls.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ", deleteBy: byBlock},
+		{name: "user_operations", columns: userOperationColumns(), csvFile: "user_operations.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ", deleteBy: byBlock},
+		{name: "logs", columns: eventLogColumns(), csvFile: "logs.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ", deleteBy: byBlock},
+		{name: "log_params", columns: logParamColumns(), csvFile: "log_params.csv", gzip: stagingGzip, parquet: stagingParquet, options: "ACCEPTINVCHARS ", deleteBy: byBlock},
+		{name: "blocks", columns: blockColumns(), csvFile: "blocks.csv", gzip: stagingGzip, parquet: stagingParquet, deleteBy: "Number IN (SELECT Number FROM stage_blocks)"},
+	}
+}
 
-	return err
+// return the staged table of blocks/transactions/events of the name
+func blockTable(name string) *stagedTable {
+	for _, s := range blockTables() {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
 }
 
-// write data of blocks/transactions/events to s3 as csv, then copy the result to redshift in a transaction
+// write data of blocks/transactions/events to s3 as csv, then copy the result to redshift in a transaction.
+// rows are copied to staging tables, and then replace existing rows of the same blocks,
+// so a block range can be reprocessed without duplicates, e.g., after a crash or by a manual backfill.
 func StoreBlocks(blocks map[string]*common.Block, s3Folder string) error {
 	if err := writeBlocksToS3(blocks, s3Folder); err != nil {
 		return err
@@ -153,39 +187,13 @@ func StoreBlocks(blocks map[string]*common.Block, s3Folder string) error {
 	}
 	ctx := context.Background()
 
-	// copy transactions
-	sql := fmt.Sprintf(`COPY eth.transactions (%s) FROM 's3://%s/%s/transactions.csv' IAM_ROLE '%s' REGION '%s' TIMEFORMAT 'auto' ACCEPTINVCHARS STATUPDATE ON CSV`,
-		strings.Join(transactionColumns(), ","), bucket.name, s3Folder, bucket.copyRole, bucket.region)
-	glog.Info("Execute sql: ", sql)
-	if _, err := tx.Exec(ctx, sql); err != nil {
-		glog.Warning("rollback copy transactions")
-		tx.Rollback(ctx)
-		deleteS3Folder(s3Folder)
-		return err
-	}
-
-	// copy event logs
-	sql = fmt.Sprintf(`COPY eth.logs (%s) FROM 's3://%s/%s/logs.csv' IAM_ROLE '%s' REGION '%s' TIMEFORMAT 'auto' ACCEPTINVCHARS STATUPDATE ON CSV`,
-		strings.Join(eventLogColumns(), ","), bucket.name, s3Folder, bucket.copyRole, bucket.region)
-	glog.Info("Execute sql: ", sql)
-	if _, err := tx.Exec(ctx, sql); err != nil {
-		glog.Warning("rollback copy event logs")
-		tx.Rollback(ctx)
-		deleteS3Folder(s3Folder)
-		return err
-	}
-
-	// copy blocks
-	sql = fmt.Sprintf(`COPY eth.blocks (%s) FROM 's3://%s/%s/blocks.csv' IAM_ROLE '%s' REGION '%s' TIMEFORMAT 'auto' STATUPDATE ON CSV`,
-		strings.Join(blockColumns(), ","), bucket.name, s3Folder, bucket.copyRole, bucket.region)
-	glog.Info("Execute sql: ", sql)
-	if _, err := tx.Exec(ctx, sql); err != nil {
+	if err := mergeStagedTables(tx, ctx, blockTables(), s3Folder, false); err != nil {
 		glog.Warning("rollback copy blocks")
 		tx.Rollback(ctx)
-		deleteS3Folder(s3Folder)
+		deleteStagedFolder(s3Folder)
 		return err
 	}
 	err = tx.Commit(ctx)
-	deleteS3Folder(s3Folder)
+	deleteStagedFolder(s3Folder)
 	return err
 }
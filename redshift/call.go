@@ -0,0 +1,68 @@
+package redshift
+
+import (
+	"github.com/open-dovetail/eth-track/common"
+)
+
+type copyFromInnerCalls struct {
+	rows []*common.InnerCall
+	idx  int
+}
+
+// column names for batch insert or copy
+func innerCallColumns() []string {
+	columns := []string{"TxnHash", "Path", "BlockNumber", "FromAddress", "ToAddress", "Operation", "Value", "ExactValue", "BlockTime", "Input", "Method",
+		"Standard", "ArgsLen"}
+	return append(columns, paramColumns()...)
+}
+
+// implement pgx.CopyFromSource interface, return tuple of values in order of innerCallColumns()
+func (c *copyFromInnerCalls) Values() ([]interface{}, error) {
+	call := c.rows[c.idx]
+	var v []interface{}
+	v = append(v, common.HexToFixedString(call.TxnHash, 64))
+	v = append(v, truncateString(call.Path, 64))
+	v = append(v, call.BlockNumber)
+	v = append(v, common.HexToFixedString(call.From, 40))
+	v = append(v, common.HexToFixedString(call.To, 40))
+	v = append(v, truncateString(call.Operation, 12))
+	v = append(v, common.BigIntToFloat(call.Value))
+	v = append(v, common.BigIntToString(call.Value))
+	v = append(v, common.SecondsToDateTime(call.BlockTime))
+	if len(call.Params) > 0 && len(call.Params) <= 5 {
+		v = append(v, []byte{})
+	} else {
+		v = append(v, filterBytesByLength(call.Input, 16384))
+	}
+	v = append(v, truncateString(call.Method, 256))
+	v = append(v, truncateString(call.Standard, 16))
+	v = append(v, len(call.Params))
+	v = appendParamValues(v, call.Params)
+	return v, nil
+}
+
+func (c *copyFromInnerCalls) Next() bool {
+	c.idx++
+	return c.idx < len(c.rows)
+}
+
+func (c *copyFromInnerCalls) Err() error {
+	return nil
+}
+
+// write inner calls of batch transactions in specified blocks to s3 as a csv file.
+func writeInnerCallsToS3(blocks map[string]*common.Block, s3Folder string) (int, error) {
+	callCount := 0
+	if len(blocks) == 0 {
+		return callCount, nil
+	}
+
+	source := &copyFromInnerCalls{idx: -1}
+	for _, b := range blocks {
+		for _, t := range b.Transactions {
+			callCount += len(t.Calls)
+			source.rows = append(source.rows, t.Calls...)
+		}
+	}
+	return callCount, writeStagedRows(s3Folder, blockTable("calls"), source)
+}
@@ -89,6 +89,11 @@ func Close() {
 	}
 }
 
+// return true if redshift connection is initialized, or false in sink-only mode without database
+func IsConnected() bool {
+	return db != nil
+}
+
 // acquires a connection, executes query, then release the connection, e.g.
 // rows, err := c.Query(`select name, age from users where age > $1`, 21)
 // var name string
@@ -2,7 +2,6 @@ package redshift
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
@@ -18,7 +17,9 @@ type copyFromContracts struct {
 
 // column names for batch insert or copy
 func contractColumns() []string {
-	return []string{"Address", "Name", "Symbol", "Decimals", "TotalSupply", "LastEventDate", "LastErrorDate", "ABI"}
+	return []string{"Address", "Name", "Symbol", "Decimals", "TotalSupply", "LastEventDate", "LastErrorDate", "ABI",
+		"ContractName", "Compiler", "Optimized", "OptimizerRuns", "License", "IsProxy", "Implementation", "AddressType",
+		"CodeHash", "CloneTarget", "TokenStandard", "ExactSupply"}
 }
 
 // implement pgx.CopyFromSource interface,  return tuple of values in order of contractColumns()
@@ -34,6 +35,18 @@ func (c *copyFromContracts) Values() ([]interface{}, error) {
 	v = append(v, common.SecondsToDateTime(contract.LastEventDate).Format("2006-01-02"))
 	v = append(v, common.SecondsToDateTime(contract.LastErrorDate).Format("2006-01-02"))
 	v = append(v, filterStringByLength(contract.ABI, 1024*31))
+	v = append(v, truncateString(contract.ContractName, 256))
+	v = append(v, truncateString(contract.Compiler, 64))
+	v = append(v, contract.Optimized)
+	v = append(v, contract.OptimizerRuns)
+	v = append(v, truncateString(contract.License, 64))
+	v = append(v, contract.IsProxy)
+	v = append(v, common.HexToFixedString(contract.Implementation, 40))
+	v = append(v, int16(contract.AddressType))
+	v = append(v, truncateString(contract.CodeHash, 64))
+	v = append(v, common.HexToFixedString(contract.CloneTarget, 40))
+	v = append(v, truncateString(contract.TokenStandard, 16))
+	v = append(v, bigIntToDecimal(contract.ExactSupply))
 	//fmt.Println("Copy contract", v[0])
 	return v, nil
 }
@@ -47,28 +60,60 @@ func (c *copyFromContracts) Err() error {
 	return nil
 }
 
-// batch insert contract values in a DB transaction.
+// contracts table, merged from staging table to replace existing contracts of the same address
+func contractTable() *stagedTable {
+	return &stagedTable{
+		name:     "contracts",
+		columns:  contractColumns(),
+		csvFile:  "contracts.csv",
+		options:  "ACCEPTINVCHARS ",
+		deleteBy: "Address IN (SELECT Address FROM stage_contracts)",
+	}
+}
+
+// batch insert contract values in a DB transaction, and replace existing contracts of the same address.
 func InsertContracts(contracts map[string]*common.Contract) error {
 	if len(contracts) == 0 {
 		return nil
 	}
 
-	source := &copyFromContracts{idx: -1}
+	// composeBatchInsert reads the current row before calling Next()
+	source := &copyFromContracts{}
 	for _, v := range contracts {
 		source.rows = append(source.rows, v)
 	}
+	table := contractTable()
 	// CopyFrom does not work for redshift probably because the postgres copy protocol is not supported by redshift
 	//rows, err := db.CopyFrom(pgx.Identifier{"eth", "contracts"}, columns, source)
-	sql, err := composeBatchInsert("eth.contracts", contractColumns(), source)
+	sql, err := composeBatchInsert(table.stageName(), table.columns, source)
 	//fmt.Println("Insert contracts:", sql)
 	if err != nil {
 		return err
 	}
-	if err := db.Exec(sql); err != nil {
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := table.create(tx, ctx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql); err != nil {
 		glog.Errorf("Failed to store contracts %+v: %s", err, sql)
+		tx.Rollback(ctx)
 		return err
 	}
-	return nil
+	if err := table.delete(tx, ctx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	if err := table.merge(tx, ctx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 // write specified contracts to s3 as a csv file.
@@ -88,13 +133,21 @@ func writeContractsToS3(contracts map[string]*common.Contract, csvFile string) e
 
 	//fmt.Println("Write contracts to s3:", string(data))
 	glog.Infof("Write %d contracts to s3", len(contracts))
-	_, err = writeS3File(csvFile, data)
+	err = objectStore.WriteFile(csvFile, data)
 	return err
 }
 
 // write data of contracts to s3 as csv, then copy the result to redshift in a transaction
 func StoreContracts(contracts map[string]*common.Contract) error {
-	csvFile := "contracts.csv"
+	if !IsConnected() {
+		// contracts are stored in the local contract file in sink-only mode
+		var list []*common.Contract
+		for _, c := range contracts {
+			list = append(list, c)
+		}
+		return appendContracts(list...)
+	}
+	csvFile := contractTable().csvFile
 	if err := writeContractsToS3(contracts, csvFile); err != nil {
 		return err
 	}
@@ -104,19 +157,16 @@ func StoreContracts(contracts map[string]*common.Contract) error {
 		return err
 	}
 
-	// copy contracts
+	// copy contracts to staging table, and replace existing contracts of the same address
 	ctx := context.Background()
-	sql := fmt.Sprintf(`COPY eth.contracts (%s) FROM 's3://%s/%s' IAM_ROLE '%s' REGION '%s' TIMEFORMAT 'auto' ACCEPTINVCHARS STATUPDATE ON CSV`,
-		strings.Join(contractColumns(), ","), bucket.name, csvFile, bucket.copyRole, bucket.region)
-	glog.Info("Execute sql: ", sql)
-	if _, err := tx.Exec(ctx, sql); err != nil {
+	if err := mergeStagedTables(tx, ctx, []*stagedTable{contractTable()}, "", false); err != nil {
 		tx.Rollback(ctx)
-		deleteS3File(csvFile)
+		deleteStagedFile(csvFile)
 		return err
 	}
 
 	err = tx.Commit(ctx)
-	deleteS3File(csvFile)
+	deleteStagedFile(csvFile)
 	return err
 }
 
@@ -125,6 +175,9 @@ func UpdateContract(contract *common.Contract) error {
 	if contract == nil {
 		return nil
 	}
+	if !IsConnected() {
+		return appendContracts(contract)
+	}
 	sql := "UPDATE eth.contracts SET LastEventDate = $1, LastErrorDate = $2 WHERE Address = $3"
 	return db.Exec(sql, common.SecondsToDateTime(contract.LastEventDate),
 		common.SecondsToDateTime(contract.LastErrorDate), common.HexToFixedString(contract.Address, 40))
@@ -136,7 +189,9 @@ func InsertContract(contract *common.Contract) error {
 	}
 	abi := filterStringByLength(contract.ABI, 1024*31)
 
-	sql := "INSERT INTO eth.contracts (Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	sql := `INSERT INTO eth.contracts (Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI,
+		ContractName, Compiler, Optimized, OptimizerRuns, License, IsProxy, Implementation, AddressType, CodeHash, CloneTarget,
+		TokenStandard, ExactSupply) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`
 	return db.Exec(sql,
 		common.HexToFixedString(contract.Address, 40),
 		truncateString(contract.Name, 256),
@@ -145,19 +200,46 @@ func InsertContract(contract *common.Contract) error {
 		contract.TotalSupply,
 		common.SecondsToDateTime(contract.LastEventDate),
 		common.SecondsToDateTime(contract.LastErrorDate),
-		abi)
+		abi,
+		truncateString(contract.ContractName, 256),
+		truncateString(contract.Compiler, 64),
+		contract.Optimized,
+		contract.OptimizerRuns,
+		truncateString(contract.License, 64),
+		contract.IsProxy,
+		common.HexToFixedString(contract.Implementation, 40),
+		int16(contract.AddressType),
+		truncateString(contract.CodeHash, 64),
+		common.HexToFixedString(contract.CloneTarget, 40),
+		truncateString(contract.TokenStandard, 16),
+		bigIntToDecimal(contract.ExactSupply))
+}
+
+// convert address stored as fixed string to hex with prefix 0x, or blank if address is not set
+func addressFromFixedString(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return ""
+	}
+	return "0x" + s
 }
 
 // acquires a connection, fetch one contract by address, then release the connection
 func QueryContract(address string) (*common.Contract, error) {
-	sql := `SELECT Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI 
-		FROM eth.contracts WHERE Address = $1`
+	if !IsConnected() {
+		return fileContract(address), nil
+	}
+	sql := `SELECT Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI,
+		ContractName, Compiler, Optimized, OptimizerRuns, License, IsProxy, Implementation, AddressType, CodeHash, CloneTarget,
+		TokenStandard FROM eth.contracts WHERE Address = $1`
 	rows, err := db.Query(sql, common.HexToFixedString(address, 40))
 	if err != nil {
 		return nil, err
 	}
 	contract := &common.Contract{Address: address}
 	var lastEventDate, lastErrorDate time.Time
+	var implementation, cloneTarget string
+	var addressType int16
 	ok, err := ScanRow(rows,
 		&contract.Name,
 		&contract.Symbol,
@@ -166,6 +248,17 @@ func QueryContract(address string) (*common.Contract, error) {
 		&lastEventDate,
 		&lastErrorDate,
 		&contract.ABI,
+		&contract.ContractName,
+		&contract.Compiler,
+		&contract.Optimized,
+		&contract.OptimizerRuns,
+		&contract.License,
+		&contract.IsProxy,
+		&implementation,
+		&addressType,
+		&contract.CodeHash,
+		&cloneTarget,
+		&contract.TokenStandard,
 	)
 	if err != nil {
 		return nil, err
@@ -175,6 +268,10 @@ func QueryContract(address string) (*common.Contract, error) {
 	}
 	contract.LastEventDate = lastEventDate.Unix()
 	contract.LastErrorDate = lastErrorDate.Unix()
+	contract.Implementation = addressFromFixedString(implementation)
+	contract.AddressType = common.AddressType(addressType)
+	contract.CloneTarget = addressFromFixedString(cloneTarget)
+	contract.CodeHash = strings.TrimSpace(contract.CodeHash)
 	if glog.V(2) {
 		glog.Infoln("Query contract", contract.Address, contract.Symbol, contract.TotalSupply, contract.LastEventDate)
 		glog.Infoln("contract ABI", contract.ABI)
@@ -190,6 +287,8 @@ type contractIterator struct {
 func (r *contractIterator) Value() interface{} {
 	contract := &common.Contract{}
 	var lastEventDate, lastErrorDate time.Time
+	var implementation, cloneTarget string
+	var addressType int16
 	r.rows.Scan(
 		&contract.Address,
 		&contract.Name,
@@ -198,8 +297,23 @@ func (r *contractIterator) Value() interface{} {
 		&contract.TotalSupply,
 		&lastEventDate,
 		&lastErrorDate,
-		&contract.ABI)
+		&contract.ABI,
+		&contract.ContractName,
+		&contract.Compiler,
+		&contract.Optimized,
+		&contract.OptimizerRuns,
+		&contract.License,
+		&contract.IsProxy,
+		&implementation,
+		&addressType,
+		&contract.CodeHash,
+		&cloneTarget,
+		&contract.TokenStandard)
 	contract.Address = "0x" + contract.Address
+	contract.Implementation = addressFromFixedString(implementation)
+	contract.AddressType = common.AddressType(addressType)
+	contract.CloneTarget = addressFromFixedString(cloneTarget)
+	contract.CodeHash = strings.TrimSpace(contract.CodeHash)
 	contract.LastEventDate = lastEventDate.Unix()
 	contract.LastErrorDate = lastErrorDate.Unix()
 	return contract
@@ -216,12 +330,37 @@ func (r *contractIterator) Close() {
 // acquires a connection and query contracts that are used in recent block days.
 // must scan to end of the resultset to release the connection.
 func QueryContracts(days int) (common.Iterator, error) {
+	if !IsConnected() {
+		return fileContracts(days), nil
+	}
 	evtDt := time.Now().Add(time.Duration(-days*24) * time.Hour)
-	sql := `SELECT Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI 
-		FROM eth.contracts WHERE LastEventDate > $1`
+	sql := `SELECT Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI,
+		ContractName, Compiler, Optimized, OptimizerRuns, License, IsProxy, Implementation, AddressType, CodeHash, CloneTarget,
+		TokenStandard FROM eth.contracts WHERE LastEventDate > $1`
 	rows, err := db.Query(sql, evtDt)
 	if err != nil {
 		return nil, err
 	}
 	return &contractIterator{rows: rows}, err
 }
+
+// acquires a connection, fetch a contract of valid ABI by hash of its runtime code, then release the connection
+// contracts in the local contract file are not searched by code hash, since code hashes of cached contracts are known already.
+func QueryContractByCodeHash(codeHash string) (*common.Contract, error) {
+	if !IsConnected() {
+		return nil, nil
+	}
+	sql := `SELECT Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI,
+		ContractName, Compiler, Optimized, OptimizerRuns, License, IsProxy, Implementation, AddressType, CodeHash, CloneTarget,
+		TokenStandard FROM eth.contracts WHERE CodeHash = $1 AND ABI <> '' LIMIT 1`
+	rows, err := db.Query(sql, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	iter := &contractIterator{rows: rows}
+	defer iter.Close()
+	if iter.Next() {
+		return iter.Value().(*common.Contract), nil
+	}
+	return nil, nil
+}
@@ -48,4 +48,16 @@ func TestInsertContracts(t *testing.T) {
 	// check UTC date conversion
 	assert.Equal(t, d, c.LastEventDate, "query result does not match lastEventDate")
 	assert.NotEmpty(t, c.ABI, "query result ABI should not be empty")
+
+	// insert the contract again should replace the existing contract
+	contract.Symbol = "DAI2"
+	err = InsertContracts(map[string]*common.Contract{address: contract})
+	assert.NoError(t, err, "Re-insert contract should not throw exception")
+	var count int64
+	err = db.QueryRow("SELECT count(*) FROM eth.contracts WHERE Address = $1", common.HexToFixedString(address, 40)).Scan(&count)
+	assert.NoError(t, err, "count contracts should not throw exception")
+	assert.Equal(t, int64(1), count, "re-insert should not duplicate contract")
+	c, err = QueryContract(address)
+	require.NoError(t, err, "query contract should not throw exception")
+	assert.Equal(t, "DAI2", c.Symbol, "re-insert should replace symbol")
 }
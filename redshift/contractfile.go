@@ -0,0 +1,190 @@
+package redshift
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+)
+
+// local NDJSON file of contracts used in sink-only mode without database, so ABIs fetched from etherscan are reused after restart.
+// each line is a contract, and a later line replaces an earlier line of the same address.
+var contractFile struct {
+	sync.Mutex
+	writer    *os.File                    // file opened for append, nil if contracts are not stored in a local file
+	contracts map[string]*common.Contract // latest contracts in the file with address as key
+}
+
+// store contracts in a local NDJSON file instead of database, e.g., when decoded blocks are only exported to sinks.
+// contracts in an existing file are loaded, and the file is compacted to keep only the latest line of each contract.
+func SetContractFile(path string) error {
+	contracts, err := readContractFile(path)
+	if err != nil {
+		return err
+	}
+
+	// rewrite the file to a temporary file and rename it, so a crash does not leave a partial file
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create contract file %s", tmp)
+	}
+	w := bufio.NewWriter(f)
+	for _, c := range contracts {
+		if err := writeContractLine(w, c); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "Failed to write contract file %s", tmp)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	writer, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open contract file %s", path)
+	}
+	contractFile.Lock()
+	defer contractFile.Unlock()
+	if contractFile.writer != nil {
+		contractFile.writer.Close()
+	}
+	contractFile.writer = writer
+	contractFile.contracts = contracts
+	glog.Infof("Loaded %d contracts from contract file %s", len(contracts), path)
+	return nil
+}
+
+// close the contract file, so contracts are no longer stored in a local file
+func closeContractFile() {
+	contractFile.Lock()
+	defer contractFile.Unlock()
+	if contractFile.writer != nil {
+		contractFile.writer.Close()
+	}
+	contractFile.writer = nil
+	contractFile.contracts = nil
+}
+
+// read contracts from a NDJSON file, or return empty result if the file does not exist.
+// invalid lines are skipped, e.g., the last line partially written before a crash.
+func readContractFile(path string) (map[string]*common.Contract, error) {
+	contracts := make(map[string]*common.Contract)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return contracts, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open contract file %s", path)
+	}
+	defer f.Close()
+
+	// ABI of a contract may exceed the default line size of bufio.Scanner, so read lines of any size
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			c := &common.Contract{}
+			if e := json.Unmarshal(line, c); e != nil || len(c.Address) == 0 {
+				glog.Warningf("Skip invalid line in contract file %s: %v", path, e)
+			} else {
+				contracts[c.Address] = c
+			}
+		}
+		if err == io.EOF {
+			return contracts, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read contract file %s", path)
+		}
+	}
+}
+
+// write a contract as a line of JSON without parsed methods and events, which are parsed from ABI when it is loaded
+func writeContractLine(w io.Writer, contract *common.Contract) error {
+	c := *contract
+	c.Methods = nil
+	c.Events = nil
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to serialize contract %s", contract.Address)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// append contracts to the contract file, and replace loaded contracts of the same address.
+// do nothing if contracts are not stored in a local file.
+func appendContracts(contracts ...*common.Contract) error {
+	contractFile.Lock()
+	defer contractFile.Unlock()
+	if contractFile.writer == nil {
+		return nil
+	}
+	for _, c := range contracts {
+		if err := writeContractLine(contractFile.writer, c); err != nil {
+			return errors.Wrapf(err, "Failed to append contract %s to contract file", c.Address)
+		}
+		copied := *c
+		copied.Methods = nil
+		copied.Events = nil
+		contractFile.contracts[c.Address] = &copied
+	}
+	return nil
+}
+
+// return a copy of the contract of an address in the contract file, or nil if it is not found
+func fileContract(address string) *common.Contract {
+	contractFile.Lock()
+	defer contractFile.Unlock()
+	if c, ok := contractFile.contracts[address]; ok {
+		copied := *c
+		return &copied
+	}
+	return nil
+}
+
+// return contracts in the contract file that are used in recent days
+func fileContracts(days int) *contractList {
+	evtTime := time.Now().Add(time.Duration(-days*24) * time.Hour).Unix()
+	contractFile.Lock()
+	defer contractFile.Unlock()
+	result := &contractList{idx: -1}
+	for _, c := range contractFile.contracts {
+		if c.LastEventDate > evtTime {
+			copied := *c
+			result.contracts = append(result.contracts, &copied)
+		}
+	}
+	return result
+}
+
+// implements common.Iterator interface for contracts loaded from the contract file
+type contractList struct {
+	contracts []*common.Contract
+	idx       int
+}
+
+func (r *contractList) Next() bool {
+	r.idx++
+	return r.idx < len(r.contracts)
+}
+
+func (r *contractList) Value() interface{} {
+	return r.contracts[r.idx]
+}
+
+func (r *contractList) Close() {}
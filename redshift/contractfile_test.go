@@ -0,0 +1,78 @@
+package redshift
+
+// Run all unit test: `go test -v`
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo/abi"
+)
+
+func TestContractFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contracts.ndjson")
+	require.NoError(t, SetContractFile(path), "set missing contract file should not throw exception")
+	t.Cleanup(closeContractFile)
+
+	dai := "0x6b175474e89094c44da98b954eedeac495271d0f"
+	eoa := "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+	now := common.RoundToUTCDate(time.Now().Unix())
+	contract := &common.Contract{
+		Address:       dai,
+		AddressType:   common.ContractAddress,
+		Symbol:        "DAI",
+		Decimals:      18,
+		LastEventDate: now,
+		ABI:           `[{"name":"totalSupply","type":"function","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`,
+		Methods:       map[string]*abi.Method{},
+	}
+	require.NoError(t, appendContracts(contract, &common.Contract{Address: eoa, AddressType: common.EOAAddress, LastEventDate: now - 60*24*60*60}),
+		"append contracts should not throw exception")
+
+	// a later line replaces the contract of the same address
+	contract.LastErrorDate = now
+	require.NoError(t, appendContracts(contract), "append updated contract should not throw exception")
+	c := fileContract(dai)
+	require.NotNil(t, c, "appended contract should be found")
+	assert.Equal(t, now, c.LastErrorDate, "contract should be updated by the last line")
+	assert.Nil(t, c.Methods, "methods should not be stored in contract file")
+
+	// simulate a partial line written before a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err, "open contract file should not throw exception")
+	_, err = f.WriteString(`{"Address":"0x`)
+	require.NoError(t, err, "write partial line should not throw exception")
+	f.Close()
+
+	// reload and compact the file after restart
+	require.NoError(t, SetContractFile(path), "reload contract file should not throw exception")
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err, "read contract file should not throw exception")
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "contract file should be compacted to 1 line per contract")
+
+	c = fileContract(dai)
+	require.NotNil(t, c, "contract should be loaded from file")
+	assert.Equal(t, "DAI", c.Symbol, "symbol of loaded contract")
+	assert.Equal(t, contract.ABI, c.ABI, "ABI of loaded contract")
+	assert.Equal(t, now, c.LastErrorDate, "error date of loaded contract")
+	c = fileContract(eoa)
+	require.NotNil(t, c, "EOA should be loaded from file")
+	assert.Equal(t, common.EOAAddress, c.AddressType, "address type of loaded EOA")
+	assert.Nil(t, fileContract("0x0000000000000000000000000000000000000001"), "unknown address should not be found")
+
+	// only contracts used in recent days are cached at startup
+	iter := fileContracts(30)
+	var addrs []string
+	for iter.Next() {
+		addrs = append(addrs, iter.Value().(*common.Contract).Address)
+	}
+	iter.Close()
+	assert.Equal(t, []string{dai}, addrs, "recent contracts in contract file")
+}
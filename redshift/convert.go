@@ -208,6 +208,47 @@ func composeCSVData(srcRows pgx.CopyFromSource) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// column names of the first 5 params of a transaction, inner call, user operation or event log
+func paramColumns() []string {
+	var columns []string
+	for i := 1; i <= 5; i++ {
+		n := strconv.Itoa(i)
+		columns = append(columns, "Arg_"+n, "S_Value_"+n, "F_Value_"+n, "E_Value_"+n)
+	}
+	return columns
+}
+
+// append name, string, float64 and exact decimal values of the first 5 params in order of paramColumns()
+func appendParamValues(v []interface{}, params []*common.NamedValue) []interface{} {
+	for i := 0; i < 5; i++ {
+		if i < len(params) {
+			v = append(v, truncateString(params[i].Name, 256))
+			s, f := convertNamedValue(params[i])
+			v = append(v, truncateString(s, 4096))
+			v = append(v, f)
+			if d, ok := common.ParamToDecimal(params[i]); ok {
+				v = append(v, d)
+			} else {
+				v = append(v, nil)
+			}
+		} else {
+			v = append(v, nil)
+			v = append(v, nil)
+			v = append(v, 0)
+			v = append(v, nil)
+		}
+	}
+	return v
+}
+
+// return exact decimal string of a big integer, or nil if it is not set
+func bigIntToDecimal(i *big.Int) interface{} {
+	if i == nil {
+		return nil
+	}
+	return i.String()
+}
+
 // convert named param value to string or float64 for database
 func convertNamedValue(v *common.NamedValue) (string, float64) {
 	value := v.Value
@@ -0,0 +1,58 @@
+package redshift
+
+// Run all unit test: `go test -v`
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+)
+
+func TestExactParamValues(t *testing.T) {
+	maxUint, ok := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok, "max uint256 should be parsed")
+	params := []*common.NamedValue{
+		{Name: "spender", Kind: abi.KindAddress, Value: web3.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d")},
+		{Name: "amount", Kind: abi.KindUInt, Value: maxUint},
+		{Name: "tick", Kind: abi.KindInt, Value: big.NewInt(-887272)},
+		{Name: "decimals", Kind: abi.KindUInt, Value: uint8(18)},
+	}
+	v := appendParamValues(nil, params)
+	require.Equal(t, len(paramColumns()), len(v), "values should match param columns")
+	assert.Nil(t, v[3], "address param should not have exact value")
+	assert.Equal(t, maxUint.String(), v[7], "exact value of uint256 param")
+	assert.Equal(t, "-887272", v[11], "exact value of int24 param")
+	assert.Equal(t, "18", v[15], "exact value of uint8 param")
+	assert.Nil(t, v[19], "missing param should not have exact value")
+}
+
+func TestParamRowValues(t *testing.T) {
+	maxUint, ok := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok, "max uint256 should be parsed")
+	keys := []interface{}{uint64(100), uint64(3), "0x01"}
+	source := &copyFromParams{idx: -1, rows: []*paramRow{
+		{keys: keys, seq: 0, param: &common.NamedValue{Name: "to", Kind: abi.KindAddress, Value: web3.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d")}},
+		{keys: keys, seq: 1, param: &common.NamedValue{Name: "value", Kind: abi.KindUInt, Value: maxUint}},
+	}}
+
+	require.True(t, source.Next(), "source should contain first param")
+	v, err := source.Values()
+	require.NoError(t, err, "values of address param should not throw error")
+	require.Equal(t, len(logParamColumns()), len(v), "values should match log param columns")
+	assert.Equal(t, 0, v[3], "sequence of first param")
+	assert.Equal(t, "to", v[4], "name of first param")
+	assert.Nil(t, v[8], "address param should not have exact value")
+
+	require.True(t, source.Next(), "source should contain second param")
+	v, err = source.Values()
+	require.NoError(t, err, "values of uint256 param should not throw error")
+	assert.Equal(t, "value", v[4], "name of second param")
+	assert.Equal(t, "Uint", v[5], "kind of second param")
+	assert.Equal(t, maxUint.String(), v[8], "exact value of uint256 param")
+	assert.False(t, source.Next(), "source should contain 2 params")
+}
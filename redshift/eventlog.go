@@ -2,7 +2,6 @@ package redshift
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/jackc/pgx/v4"
@@ -16,9 +15,8 @@ type copyFromEventLogs struct {
 
 // column names for batch insert or copy
 func eventLogColumns() []string {
-	return []string{"BlockNumber", "LogIndex", "TxnIndex", "TxnHash", "Address", "BlockTime", "Data", "Event", "ArgsLen",
-		"Arg_1", "S_Value_1", "F_Value_1", "Arg_2", "S_Value_2", "F_Value_2", "Arg_3", "S_Value_3", "F_Value_3",
-		"Arg_4", "S_Value_4", "F_Value_4", "Arg_5", "S_Value_5", "F_Value_5"}
+	columns := []string{"BlockNumber", "LogIndex", "TxnIndex", "TxnHash", "Address", "BlockTime", "Data", "Event", "Standard", "Amount", "ArgsLen"}
+	return append(columns, paramColumns()...)
 }
 
 // implement pgx.CopyFromSource interface, return tuple of values in order of eventLogColumns()
@@ -37,19 +35,10 @@ func (c *copyFromEventLogs) Values() ([]interface{}, error) {
 		v = append(v, filterBytesByLength(eventlog.Data, 16384))
 	}
 	v = append(v, truncateString(eventlog.Event, 256))
+	v = append(v, truncateString(eventlog.Standard, 16))
+	v = append(v, eventlog.Amount)
 	v = append(v, len(eventlog.Params))
-	for i := 0; i < 5; i++ {
-		if i < len(eventlog.Params) {
-			v = append(v, truncateString(eventlog.Params[i].Name, 256))
-			s, f := convertNamedValue(eventlog.Params[i])
-			v = append(v, truncateString(s, 4096))
-			v = append(v, f)
-		} else {
-			v = append(v, nil)
-			v = append(v, nil)
-			v = append(v, 0)
-		}
-	}
+	v = appendParamValues(v, eventlog.Params)
 	//fmt.Println("Copy eventlog", v[0], v[1])
 	return v, nil
 }
@@ -104,14 +93,5 @@ func writeEventLogsToS3(blocks map[string]*common.Block, s3Folder string) (int,
 			source.rows = append(source.rows, v)
 		}
 	}
-	data, err := composeCSVData(source)
-	if err != nil {
-		return logCount, err
-	}
-
-	//fmt.Println("Write event logs to s3:", string(data))
-	s3Filename := fmt.Sprintf("%s/logs.csv", s3Folder)
-	_, err = writeS3File(s3Filename, data)
-
-	return logCount, err
+	return logCount, writeStagedRows(s3Folder, blockTable("logs"), source)
 }
@@ -78,6 +78,14 @@ func queryBlockInterval() (*BlockInterval, error) {
 	}
 	bi := NewBlockInterval([]Interval{{progress.LowBlock, progress.HiBlock}})
 
+	if !IsConnected() {
+		// sink-only mode tracks only consecutive blocks in progress file
+		if len(bi.working) > 0 {
+			bi.scheduled = bi.working[0]
+		}
+		return bi, nil
+	}
+
 	// query blocks and set blocks saved in the blocks table
 	blocks, err := SelectBlocks(int64(progress.HiBlock), int64(progress.LowBlock))
 	if err != nil {
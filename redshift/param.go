@@ -0,0 +1,94 @@
+package redshift
+
+import (
+	"github.com/open-dovetail/eth-track/common"
+)
+
+// param of a transaction or event log, stored as a row of a child table
+type paramRow struct {
+	keys      []interface{} // values of key columns of the parent row
+	seq       int
+	param     *common.NamedValue
+	blockTime int64
+}
+
+type copyFromParams struct {
+	rows []*paramRow
+	idx  int
+}
+
+// column names for copy of transaction params
+func transactionParamColumns() []string {
+	return append([]string{"TxnHash"}, paramRowColumns()...)
+}
+
+// column names for copy of event log params
+func logParamColumns() []string {
+	return append([]string{"BlockNumber", "LogIndex", "TxnHash"}, paramRowColumns()...)
+}
+
+// column names that follow the key columns of the parent row
+func paramRowColumns() []string {
+	return []string{"Seq", "Name", "Kind", "S_Value", "F_Value", "E_Value", "BlockTime"}
+}
+
+// implement pgx.CopyFromSource interface, return tuple of values in order of key columns and paramRowColumns()
+func (c *copyFromParams) Values() ([]interface{}, error) {
+	row := c.rows[c.idx]
+	v := append([]interface{}{}, row.keys...)
+	v = append(v, row.seq)
+	v = append(v, truncateString(row.param.Name, 256))
+	v = append(v, row.param.Kind.String())
+	s, f := convertNamedValue(row.param)
+	v = append(v, truncateString(s, 4096))
+	v = append(v, f)
+	if d, ok := common.ParamToDecimal(row.param); ok {
+		v = append(v, d)
+	} else {
+		v = append(v, nil)
+	}
+	v = append(v, common.SecondsToDateTime(row.blockTime))
+	return v, nil
+}
+
+func (c *copyFromParams) Next() bool {
+	c.idx++
+	return c.idx < len(c.rows)
+}
+
+func (c *copyFromParams) Err() error {
+	return nil
+}
+
+// write all params of transactions in specified blocks to s3 as a csv file.
+func writeTransactionParamsToS3(blocks map[string]*common.Block, s3Folder string) (int, error) {
+	source := &copyFromParams{idx: -1}
+	for _, b := range blocks {
+		for _, t := range b.Transactions {
+			keys := []interface{}{common.HexToFixedString(t.Hash, 64)}
+			for i, p := range t.Params {
+				source.rows = append(source.rows, &paramRow{keys: keys, seq: i, param: p, blockTime: t.BlockTime})
+			}
+		}
+	}
+	return writeParamsToS3(source, s3Folder, "transaction_params")
+}
+
+// write all params of event logs in specified blocks to s3 as a csv file.
+func writeLogParamsToS3(blocks map[string]*common.Block, s3Folder string) (int, error) {
+	source := &copyFromParams{idx: -1}
+	for _, b := range blocks {
+		for _, evt := range b.Logs {
+			keys := []interface{}{evt.BlockNumber, evt.LogIndex, common.HexToFixedString(evt.TxnHash, 64)}
+			for i, p := range evt.Params {
+				source.rows = append(source.rows, &paramRow{keys: keys, seq: i, param: p, blockTime: evt.BlockTime})
+			}
+		}
+	}
+	return writeParamsToS3(source, s3Folder, "log_params")
+}
+
+func writeParamsToS3(source *copyFromParams, s3Folder string, table string) (int, error) {
+	paramCount := len(source.rows)
+	return paramCount, writeStagedRows(s3Folder, blockTable(table), source)
+}
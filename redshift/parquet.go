@@ -0,0 +1,179 @@
+package redshift
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// DDL of redshift tables, which defines the Parquet types of staged columns
+//
+//go:embed tables.sql
+var tablesSQL string
+
+// SQL types of columns of redshift tables, keyed by table name and lower-case column name
+var columnTypes = parseColumnTypes(tablesSQL)
+
+var (
+	createTablePattern = regexp.MustCompile(`(?s)CREATE TABLE eth\.(\w+)\s*\((.*?)\);`)
+	columnPattern      = regexp.MustCompile(`^\s*(\w+)\s+([A-Z0-9]+)`)
+)
+
+// return SQL types of columns in CREATE TABLE statements, e.g., columnTypes["blocks"]["number"] = "BIGINT"
+func parseColumnTypes(ddl string) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, m := range createTablePattern.FindAllStringSubmatch(ddl, -1) {
+		columns := make(map[string]string)
+		for _, line := range strings.Split(m[2], "\n") {
+			if c := columnPattern.FindStringSubmatch(line); c != nil {
+				columns[strings.ToLower(c[1])] = c[2]
+			}
+		}
+		result[m[1]] = columns
+	}
+	return result
+}
+
+// Parquet type of a redshift column type that COPY can load
+var parquetTypes = map[string]string{
+	"CHAR":      "type=BYTE_ARRAY, convertedtype=UTF8",
+	"VARCHAR":   "type=BYTE_ARRAY, convertedtype=UTF8",
+	"VARBYTE":   "type=BYTE_ARRAY",
+	"BIGINT":    "type=INT64",
+	"INTEGER":   "type=INT32",
+	"SMALLINT":  "type=INT32",
+	"FLOAT8":    "type=DOUBLE",
+	"BOOLEAN":   "type=BOOLEAN",
+	"TIMESTAMP": "type=INT64, convertedtype=TIMESTAMP_MICROS",
+	"DATE":      "type=INT32, convertedtype=DATE",
+}
+
+// return Parquet schema of optional columns of a table in the order of staged columns
+func parquetMetadata(table string, columns []string) ([]string, error) {
+	types, ok := columnTypes[table]
+	if !ok {
+		return nil, errors.Errorf("Table %s is not defined in tables.sql", table)
+	}
+	var md []string
+	for _, c := range columns {
+		ptype, ok := parquetTypes[types[strings.ToLower(c)]]
+		if !ok {
+			return nil, errors.Errorf("No Parquet type of column %s.%s", table, c)
+		}
+		md = append(md, fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", c, ptype))
+	}
+	return md, nil
+}
+
+// convert a staged value to the Go type of Parquet writer for the redshift column type
+func parquetValue(v interface{}, sqlType string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch sqlType {
+	case "CHAR", "VARCHAR":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case "VARBYTE":
+		if b, ok := v.([]byte); ok {
+			return string(b), nil
+		}
+	case "BIGINT":
+		return toInt64(v)
+	case "INTEGER", "SMALLINT":
+		i, err := toInt64(v)
+		if err != nil || i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, errors.Errorf("value %v is not a 32-bit integer", v)
+		}
+		return int32(i), nil
+	case "FLOAT8":
+		if f, ok := v.(float64); ok {
+			return f, nil
+		}
+		i, err := toInt64(v)
+		return float64(i), err
+	case "BOOLEAN":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case "TIMESTAMP":
+		if t, ok := v.(time.Time); ok {
+			return t.UnixNano() / 1000, nil
+		}
+	case "DATE":
+		if t, ok := v.(time.Time); ok {
+			return int32(t.Unix() / 86400), nil
+		}
+	}
+	return nil, errors.Errorf("unsupported value %v of type %T for %s column", v, v, sqlType)
+}
+
+// convert an integer value to int64
+func toInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, errors.Errorf("value %v is too big for int64", v)
+		}
+		return int64(v), nil
+	}
+	return 0, errors.Errorf("value %v of type %T is not an integer", v, v)
+}
+
+// compose Parquet file content of rows of a table, which redshift can copy using FORMAT AS PARQUET
+func composeParquetData(table string, columns []string, srcRows pgx.CopyFromSource) ([]byte, error) {
+	md, err := parquetMetadata(table, columns)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	pw, err := writer.NewCSVWriterFromWriter(md, &buf, 1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create parquet writer of %s", table)
+	}
+	types := columnTypes[table]
+	for srcRows.Next() {
+		v, err := srcRows.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make([]interface{}, len(columns))
+		for i, c := range columns {
+			if row[i], err = parquetValue(v[i], types[strings.ToLower(c)]); err != nil {
+				return nil, errors.Wrapf(err, "Failed to convert %s.%s", table, c)
+			}
+		}
+		if err := pw.Write(row); err != nil {
+			return nil, errors.Wrapf(err, "Failed to write parquet row of %s", table)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to finish parquet file of %s", table)
+	}
+	return buf.Bytes(), nil
+}
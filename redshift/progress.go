@@ -1,14 +1,31 @@
 package redshift
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
 	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
 )
 
+// local JSON file of progress used in sink-only mode without database, blank to store progress in database
+var progressFile string
+
+// store progress in a local JSON file instead of database, e.g., when decoded blocks are only exported to sinks.
+// blocks stored in database are not checked in this mode, so progress includes only consecutive exported blocks.
+func SetProgressFile(path string) {
+	progressFile = path
+}
+
 // acquires a connection, update a progress row, then release the connection
 func UpdateProgress(progress *common.Progress) error {
 	if progress == nil {
 		return nil
 	}
+	if len(progressFile) > 0 {
+		return writeProgressFile(progress)
+	}
 	sql := "UPDATE eth.progress SET HiBlock=$1, LowBlock=$2 WHERE ProcessID=$3"
 	//fmt.Println("update progress", progress)
 	return db.Exec(sql,
@@ -19,6 +36,9 @@ func UpdateProgress(progress *common.Progress) error {
 
 // acquires a connection, fetch a progress row by id, then release the connection
 func QueryProgress(pid common.ProcessType) (*common.Progress, error) {
+	if len(progressFile) > 0 {
+		return readProgressFile(pid)
+	}
 	sql := `SELECT HiBlock, LowBlock FROM eth.progress WHERE ProcessID = $1`
 	rows, err := db.Query(sql, pid)
 	if err != nil {
@@ -37,3 +57,32 @@ func QueryProgress(pid common.ProcessType) (*common.Progress, error) {
 	}
 	return progress, nil
 }
+
+// write progress to a temporary file, and rename it to the progress file, so a crash does not leave a partial file
+func writeProgressFile(progress *common.Progress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	tmp := progressFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write progress file %s", tmp)
+	}
+	return os.Rename(tmp, progressFile)
+}
+
+// read progress from the progress file, or return empty progress if the file does not exist
+func readProgressFile(pid common.ProcessType) (*common.Progress, error) {
+	progress := &common.Progress{ProcessID: pid}
+	data, err := ioutil.ReadFile(progressFile)
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read progress file %s", progressFile)
+	}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, errors.Wrapf(err, "Invalid progress file %s", progressFile)
+	}
+	return progress, nil
+}
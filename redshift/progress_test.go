@@ -3,6 +3,7 @@ package redshift
 // Run all unit test: `go test -v`
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/open-dovetail/eth-track/common"
@@ -28,3 +29,31 @@ func TestUpdateProgress(t *testing.T) {
 	assert.Equal(t, uint64(14706420), p.HiBlock, "query result does not match HiBlock")
 	assert.Equal(t, uint64(14706410), p.LowBlock, "query result does not match LowBlock")
 }
+
+func TestProgressFile(t *testing.T) {
+	SetProgressFile(filepath.Join(t.TempDir(), "progress.json"))
+	t.Cleanup(func() { SetProgressFile("") })
+
+	// missing file returns empty progress
+	p, err := QueryProgress(common.AddTransaction)
+	require.NoError(t, err, "query progress of missing file should not throw exception")
+	assert.Equal(t, uint64(0), p.HiBlock, "progress of missing file should be empty")
+
+	progress := &common.Progress{
+		ProcessID: common.AddTransaction,
+		HiBlock:   14706420,
+		LowBlock:  14706410,
+	}
+	err = UpdateProgress(progress)
+	require.NoError(t, err, "Update progress file should not throw exception")
+
+	p, err = QueryProgress(common.AddTransaction)
+	require.NoError(t, err, "query progress file should not throw exception")
+	assert.Equal(t, uint64(14706420), p.HiBlock, "query result does not match HiBlock")
+	assert.Equal(t, uint64(14706410), p.LowBlock, "query result does not match LowBlock")
+
+	// block cache is initialized from progress file without database
+	bi, err := queryBlockInterval()
+	require.NoError(t, err, "query block interval should not throw exception")
+	assert.Equal(t, Interval{Low: 14706410, High: 14706420}, bi.GetScheduledBlocks(), "scheduled blocks should match progress file")
+}
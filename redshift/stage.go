@@ -0,0 +1,181 @@
+package redshift
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+)
+
+var (
+	stagingGzip    bool // true to compress staged csv files of blocks using gzip
+	stagingParquet bool // true to stage files of blocks as Parquet instead of csv
+)
+
+// if flag is true, staged csv files of blocks are compressed using gzip, and named with suffix .gz
+func SetStagingGzip(flag bool) {
+	stagingGzip = flag
+}
+
+// if flag is true, staged files of blocks are written as Parquet, and named with suffix .parquet instead of .csv
+func SetStagingParquet(flag bool) {
+	stagingParquet = flag
+}
+
+// write rows of a staged table to its file in the s3 folder as csv, gzip compressed csv, or Parquet
+func writeStagedRows(s3Folder string, s *stagedTable, srcRows pgx.CopyFromSource) error {
+	var content []byte
+	var err error
+	if s.parquet {
+		content, err = composeParquetData(s.name, s.columns, srcRows)
+	} else {
+		content, err = composeCSVData(srcRows)
+	}
+	if err != nil {
+		return err
+	}
+	if s.gzip && !s.parquet {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(content); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		content = buf.Bytes()
+	}
+	return objectStore.WriteFile(s3Folder+"/"+s.fileName(), content)
+}
+
+// table that is copied from a csv file in s3 into a staging table, and then merged into the target table
+type stagedTable struct {
+	name     string   // target table name without schema, e.g., blocks
+	columns  []string // columns of the csv file
+	csvFile  string   // csv file name in the s3 folder
+	options  string   // additional copy options
+	gzip     bool     // true if the csv file is compressed
+	parquet  bool     // true if the file is staged as Parquet instead of csv
+	deleteBy string   // condition to delete target rows that are replaced by the staged rows
+}
+
+// name of the temporary staging table of the target table
+func (s *stagedTable) stageName() string {
+	return "stage_" + s.name
+}
+
+// name of the staged file in s3 folder
+func (s *stagedTable) fileName() string {
+	if s.parquet {
+		return strings.TrimSuffix(s.csvFile, ".csv") + ".parquet"
+	}
+	if s.gzip {
+		return s.csvFile + ".gz"
+	}
+	return s.csvFile
+}
+
+// name of the manifest file that lists staged files of the table in multiple s3 folders
+func (s *stagedTable) manifestName() string {
+	return s.name + ".manifest"
+}
+
+// create a temporary staging table of the same structure as the target table.
+// temporary tables are visible only in the current session, so concurrent jobs do not interfere with each other.
+func (s *stagedTable) create(tx pgx.Tx, ctx context.Context) error {
+	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", s.stageName())
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	sql = fmt.Sprintf("CREATE TEMP TABLE %s (LIKE eth.%s)", s.stageName(), s.name)
+	glog.Info("Execute sql: ", sql)
+	_, err := tx.Exec(ctx, sql)
+	return err
+}
+
+// copy the csv or Parquet file in the s3 folder to the staging table,
+// or copy all files listed in the manifest file of the table in the s3 folder if manifest is true.
+func (s *stagedTable) copy(tx pgx.Tx, ctx context.Context, s3Folder string, manifest bool) error {
+	path := s.fileName()
+	options := s.options
+	if s.parquet {
+		// csv options do not apply to Parquet
+		options = ""
+	}
+	if manifest {
+		path = s.manifestName()
+		options += "MANIFEST "
+	}
+	if s.gzip && !s.parquet {
+		options += "GZIP "
+	}
+	if len(s3Folder) > 0 {
+		path = s3Folder + "/" + path
+	}
+	if bucket == nil || len(bucket.endpoint) > 0 || objectStore != ObjectStore(bucket) {
+		return errors.Errorf("Redshift can copy %s only from AWS s3 bucket", path)
+	}
+	sql := fmt.Sprintf(`COPY %s (%s) FROM '%s' IAM_ROLE '%s' REGION '%s' TIMEFORMAT 'auto' %sSTATUPDATE ON CSV`,
+		s.stageName(), strings.Join(s.columns, ","), objectStore.URL(path), bucket.copyRole, bucket.region, options)
+	if s.parquet {
+		sql = fmt.Sprintf(`COPY %s (%s) FROM '%s' IAM_ROLE '%s' REGION '%s' %sFORMAT AS PARQUET`,
+			s.stageName(), strings.Join(s.columns, ","), objectStore.URL(path), bucket.copyRole, bucket.region, options)
+	}
+	glog.Info("Execute sql: ", sql)
+	_, err := tx.Exec(ctx, sql)
+	return err
+}
+
+// delete target rows that are replaced by the staged rows
+func (s *stagedTable) delete(tx pgx.Tx, ctx context.Context) error {
+	sql := fmt.Sprintf("DELETE FROM eth.%s WHERE %s", s.name, s.deleteBy)
+	glog.Info("Execute sql: ", sql)
+	_, err := tx.Exec(ctx, sql)
+	return err
+}
+
+// insert staged rows into the target table, and drop the staging table
+func (s *stagedTable) merge(tx pgx.Tx, ctx context.Context) error {
+	columns := strings.Join(s.columns, ",")
+	sql := fmt.Sprintf("INSERT INTO eth.%s (%s) SELECT %s FROM %s", s.name, columns, columns, s.stageName())
+	glog.Info("Execute sql: ", sql)
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf("DROP TABLE %s", s.stageName()))
+	return err
+}
+
+// copy csv files of tables to staging tables, then replace target rows by staged rows in the db transaction.
+// if manifest is true, the s3 folder contains manifest files of tables that list csv files in multiple folders.
+// target rows of all tables are deleted before staged rows are inserted,
+// so a delete condition may refer to target rows of other tables, e.g., transactions of staged blocks.
+func mergeStagedTables(tx pgx.Tx, ctx context.Context, tables []*stagedTable, s3Folder string, manifest bool) error {
+	for _, s := range tables {
+		if err := s.create(tx, ctx); err != nil {
+			return err
+		}
+		if err := s.copy(tx, ctx, s3Folder, manifest); err != nil {
+			glog.Warningf("Failed to copy %s: %v", s.name, err)
+			return err
+		}
+	}
+	for _, s := range tables {
+		if err := s.delete(tx, ctx); err != nil {
+			glog.Warningf("Failed to delete replaced rows of %s: %v", s.name, err)
+			return err
+		}
+	}
+	for _, s := range tables {
+		if err := s.merge(tx, ctx); err != nil {
+			glog.Warningf("Failed to insert staged rows of %s: %v", s.name, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package redshift
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// object store of files staged for redshift copy, e.g., AWS s3 bucket, s3-compatible bucket, or local folder.
+// names of files and folders are relative paths separated by '/'.
+type ObjectStore interface {
+	WriteFile(name string, content []byte) error
+	ReadFile(name string) ([]byte, error)
+	DeleteFile(name string) error
+	DeleteFolder(name string) error
+	FileSize(name string) (int64, error) // size of the file in bytes
+	URL(name string) string              // URL of the file used by redshift copy or manifest
+}
+
+// singleton object store of staged files
+var objectStore ObjectStore
+
+// true to keep staged files as an archive after they are copied to redshift
+var keepStagedFiles bool
+
+// set object store of staged files, which overrides the default s3 bucket
+func SetObjectStore(store ObjectStore) {
+	objectStore = store
+}
+
+func GetObjectStore() ObjectStore {
+	return objectStore
+}
+
+// if flag is true, staged files are not deleted after they are copied to redshift
+func SetKeepStagedFiles(flag bool) {
+	keepStagedFiles = flag
+}
+
+// delete a staged file unless staged files are kept as an archive
+func deleteStagedFile(name string) {
+	if keepStagedFiles {
+		return
+	}
+	if err := objectStore.DeleteFile(name); err != nil {
+		glog.Warningf("Failed to delete staged file %s: %v", name, err)
+	}
+}
+
+// delete a folder of staged files unless staged files are kept as an archive
+func deleteStagedFolder(name string) {
+	if keepStagedFiles {
+		return
+	}
+	if err := objectStore.DeleteFolder(name); err != nil {
+		glog.Warningf("Failed to delete staged folder %s: %v", name, err)
+	}
+}
+
+// object store in a folder of local file system
+type localStore struct {
+	root string
+}
+
+// create object store in the root folder of local file system, which is created if it does not exist.
+// redshift cannot copy from local files, so it is used to develop and test staging of files without AWS.
+func NewLocalStore(root string) (ObjectStore, error) {
+	path, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create local store folder %s", path)
+	}
+	return &localStore{root: path}, nil
+}
+
+func (l *localStore) path(name string) string {
+	return filepath.Join(l.root, filepath.FromSlash(name))
+}
+
+func (l *localStore) WriteFile(name string, content []byte) error {
+	path := l.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+func (l *localStore) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(l.path(name))
+}
+
+func (l *localStore) DeleteFile(name string) error {
+	if err := os.Remove(l.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *localStore) DeleteFolder(name string) error {
+	return os.RemoveAll(l.path(name))
+}
+
+func (l *localStore) FileSize(name string) (int64, error) {
+	info, err := os.Stat(l.path(name))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (l *localStore) URL(name string) string {
+	return "file://" + filepath.ToSlash(l.path(name))
+}
@@ -6,9 +6,31 @@ CREATE TABLE eth.contracts
     Symbol VARCHAR(256),
     Decimals BIGINT,
     TotalSupply FLOAT8,
+    ExactSupply VARCHAR(80),
     LastEventDate DATE,
     LastErrorDate DATE,
-    ABI VARCHAR(32768)
+    ABI VARCHAR(32768),
+    ContractName VARCHAR(256),
+    Compiler VARCHAR(64),
+    Optimized BOOLEAN,
+    OptimizerRuns INTEGER,
+    License VARCHAR(64),
+    IsProxy BOOLEAN,
+    Implementation CHAR(40),
+    AddressType SMALLINT,
+    CodeHash CHAR(64),
+    CloneTarget CHAR(40),
+    TokenStandard VARCHAR(16)
+);
+
+DROP TABLE IF EXISTS eth.token_supply;
+CREATE TABLE eth.token_supply
+(
+    Address CHAR(40) not null,
+    TotalSupply FLOAT8,
+    ExactSupply VARCHAR(80),
+    BlockNumber BIGINT,
+    SnapshotTime TIMESTAMP sortkey
 );
 
 DROP TABLE IF EXISTS eth.blocks;
@@ -19,6 +41,7 @@ CREATE TABLE eth.blocks
     ParentHash CHAR(64),
     Miner CHAR(40),
     Difficulty FLOAT8,
+    ExactDifficulty VARCHAR(80),
     GasLimit BIGINT,
     GasUsed BIGINT,
     BlockTime TIMESTAMP
@@ -34,29 +57,116 @@ CREATE TABLE eth.transactions
     ToAddress CHAR(40),
     Input VARBYTE(64000),
     Method VARCHAR(256),
+    Standard VARCHAR(16),
     ArgsLen INTEGER,
     Arg_1 VARCHAR(256),
     S_Value_1 VARCHAR(4096),
     F_Value_1 FLOAT8,
+    E_Value_1 VARCHAR(80),
     Arg_2 VARCHAR(256),
     S_Value_2 VARCHAR(4096),
     F_Value_2 FLOAT8,
+    E_Value_2 VARCHAR(80),
     Arg_3 VARCHAR(256),
     S_Value_3 VARCHAR(4096),
     F_Value_3 FLOAT8,
+    E_Value_3 VARCHAR(80),
     Arg_4 VARCHAR(256),
     S_Value_4 VARCHAR(4096),
     F_Value_4 FLOAT8,
+    E_Value_4 VARCHAR(80),
     Arg_5 VARCHAR(256),
     S_Value_5 VARCHAR(4096),
     F_Value_5 FLOAT8,
+    E_Value_5 VARCHAR(80),
     GasPrice BIGINT,
     Gas BIGINT,
     Value FLOAT8,
+    ExactValue VARCHAR(80),
     Nonce BIGINT,
     BlockTime TIMESTAMP sortkey
 );
 
+DROP TABLE IF EXISTS eth.calls;
+CREATE TABLE eth.calls
+(
+    TxnHash CHAR(64) not null,
+    Path VARCHAR(64) not null,
+    BlockNumber BIGINT not null,
+    FromAddress CHAR(40),
+    ToAddress CHAR(40),
+    Operation VARCHAR(12),
+    Value FLOAT8,
+    ExactValue VARCHAR(80),
+    Input VARBYTE(64000),
+    Method VARCHAR(256),
+    Standard VARCHAR(16),
+    ArgsLen INTEGER,
+    Arg_1 VARCHAR(256),
+    S_Value_1 VARCHAR(4096),
+    F_Value_1 FLOAT8,
+    E_Value_1 VARCHAR(80),
+    Arg_2 VARCHAR(256),
+    S_Value_2 VARCHAR(4096),
+    F_Value_2 FLOAT8,
+    E_Value_2 VARCHAR(80),
+    Arg_3 VARCHAR(256),
+    S_Value_3 VARCHAR(4096),
+    F_Value_3 FLOAT8,
+    E_Value_3 VARCHAR(80),
+    Arg_4 VARCHAR(256),
+    S_Value_4 VARCHAR(4096),
+    F_Value_4 FLOAT8,
+    E_Value_4 VARCHAR(80),
+    Arg_5 VARCHAR(256),
+    S_Value_5 VARCHAR(4096),
+    F_Value_5 FLOAT8,
+    E_Value_5 VARCHAR(80),
+    BlockTime TIMESTAMP sortkey
+);
+
+DROP TABLE IF EXISTS eth.user_operations;
+CREATE TABLE eth.user_operations
+(
+    TxnHash CHAR(64) not null,
+    OpIndex INTEGER not null,
+    BlockNumber BIGINT not null,
+    EntryPoint CHAR(40),
+    Sender CHAR(40),
+    Nonce VARCHAR(80),
+    Factory CHAR(40),
+    Paymaster CHAR(40),
+    CallData VARBYTE(64000),
+    Method VARCHAR(256),
+    Standard VARCHAR(16),
+    ArgsLen INTEGER,
+    Arg_1 VARCHAR(256),
+    S_Value_1 VARCHAR(4096),
+    F_Value_1 FLOAT8,
+    E_Value_1 VARCHAR(80),
+    Arg_2 VARCHAR(256),
+    S_Value_2 VARCHAR(4096),
+    F_Value_2 FLOAT8,
+    E_Value_2 VARCHAR(80),
+    Arg_3 VARCHAR(256),
+    S_Value_3 VARCHAR(4096),
+    F_Value_3 FLOAT8,
+    E_Value_3 VARCHAR(80),
+    Arg_4 VARCHAR(256),
+    S_Value_4 VARCHAR(4096),
+    F_Value_4 FLOAT8,
+    E_Value_4 VARCHAR(80),
+    Arg_5 VARCHAR(256),
+    S_Value_5 VARCHAR(4096),
+    F_Value_5 FLOAT8,
+    E_Value_5 VARCHAR(80),
+    UserOpHash CHAR(64),
+    Success BOOLEAN,
+    ActualGasCost FLOAT8,
+    ActualGasUsed BIGINT,
+    BlockTime TIMESTAMP sortkey
+);
+
 DROP TABLE IF EXISTS eth.logs;
 CREATE TABLE eth.logs
 (
@@ -67,26 +177,61 @@ CREATE TABLE eth.logs
     Address CHAR(40),
     Data VARBYTE(64000),
     Event VARCHAR(256),
+    Standard VARCHAR(16),
+    Amount FLOAT8,
     ArgsLen INTEGER,
     Arg_1 VARCHAR(256),
     S_Value_1 VARCHAR(4096),
     F_Value_1 FLOAT8,
+    E_Value_1 VARCHAR(80),
     Arg_2 VARCHAR(256),
     S_Value_2 VARCHAR(4096),
     F_Value_2 FLOAT8,
+    E_Value_2 VARCHAR(80),
     Arg_3 VARCHAR(256),
     S_Value_3 VARCHAR(4096),
     F_Value_3 FLOAT8,
+    E_Value_3 VARCHAR(80),
     Arg_4 VARCHAR(256),
     S_Value_4 VARCHAR(4096),
     F_Value_4 FLOAT8,
+    E_Value_4 VARCHAR(80),
     Arg_5 VARCHAR(256),
     S_Value_5 VARCHAR(4096),
     F_Value_5 FLOAT8,
+    E_Value_5 VARCHAR(80),
     BlockTime TIMESTAMP sortkey,
     primary key(BlockNumber, LogIndex)
 );
 
+DROP TABLE IF EXISTS eth.transaction_params;
+CREATE TABLE eth.transaction_params
+(
+    TxnHash CHAR(64) not null,
+    Seq INTEGER not null,
+    Name VARCHAR(256),
+    Kind VARCHAR(16),
+    S_Value VARCHAR(4096),
+    F_Value FLOAT8,
+    E_Value VARCHAR(80),
+    BlockTime TIMESTAMP sortkey
+);
+
+DROP TABLE IF EXISTS eth.log_params;
+CREATE TABLE eth.log_params
+(
+    BlockNumber BIGINT not null,
+    LogIndex BIGINT not null,
+    TxnHash CHAR(64),
+    Seq INTEGER not null,
+    Name VARCHAR(256),
+    Kind VARCHAR(16),
+    S_Value VARCHAR(4096),
+    F_Value FLOAT8,
+    E_Value VARCHAR(80),
+    BlockTime TIMESTAMP sortkey
+);
+
 DROP TABLE IF EXISTS eth.progress;
 CREATE TABLE eth.progress
 (
@@ -0,0 +1,75 @@
+package redshift
+
+import (
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+)
+
+type copyFromTokenSupplies struct {
+	rows []*common.TokenSupply
+	idx  int
+}
+
+// column names for batch insert or copy
+func tokenSupplyColumns() []string {
+	return []string{"Address", "TotalSupply", "ExactSupply", "BlockNumber", "SnapshotTime"}
+}
+
+// implement pgx.CopyFromSource interface, return tuple of values in order of tokenSupplyColumns()
+func (c *copyFromTokenSupplies) Values() ([]interface{}, error) {
+	supply := c.rows[c.idx]
+	var v []interface{}
+	v = append(v, common.HexToFixedString(supply.Address, 40))
+	v = append(v, supply.TotalSupply)
+	v = append(v, bigIntToDecimal(supply.ExactSupply))
+	v = append(v, supply.BlockNumber)
+	v = append(v, common.SecondsToDateTime(supply.SnapshotTime))
+	return v, nil
+}
+
+func (c *copyFromTokenSupplies) Next() bool {
+	c.idx++
+	return c.idx < len(c.rows)
+}
+
+func (c *copyFromTokenSupplies) Err() error {
+	return nil
+}
+
+// batch insert snapshots of token supply
+func InsertTokenSupplies(supplies []*common.TokenSupply) error {
+	if len(supplies) == 0 || !IsConnected() {
+		return nil
+	}
+
+	// composeBatchInsert reads the current row before calling Next()
+	source := &copyFromTokenSupplies{rows: supplies}
+	sql, err := composeBatchInsert("eth.token_supply", tokenSupplyColumns(), source)
+	if err != nil {
+		return err
+	}
+	if err := db.Exec(sql); err != nil {
+		glog.Errorf("Failed to store %d token supplies: %+v", len(supplies), err)
+		return err
+	}
+	return nil
+}
+
+// acquires a connection, updates token metadata and total supply of a contract, then release the connection
+func UpdateTokenMetadata(contract *common.Contract) error {
+	if contract == nil {
+		return nil
+	}
+	if !IsConnected() {
+		return appendContracts(contract)
+	}
+	sql := "UPDATE eth.contracts SET Name = $1, Symbol = $2, Decimals = $3, TotalSupply = $4, ExactSupply = $5, TokenStandard = $6 WHERE Address = $7"
+	return db.Exec(sql,
+		truncateString(contract.Name, 256),
+		truncateString(contract.Symbol, 256),
+		contract.Decimals,
+		contract.TotalSupply,
+		bigIntToDecimal(contract.ExactSupply),
+		truncateString(contract.TokenStandard, 16),
+		common.HexToFixedString(contract.Address, 40))
+}
@@ -2,7 +2,6 @@ package redshift
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/jackc/pgx/v4"
@@ -16,10 +15,9 @@ type copyFromTransactions struct {
 
 // column names for batch insert or copy
 func transactionColumns() []string {
-	return []string{"Hash", "BlockNumber", "TxnIndex", "FromAddress", "ToAddress", "GasPrice", "Gas",
-		"Value", "Nonce", "BlockTime", "Input", "Method", "ArgsLen",
-		"Arg_1", "S_Value_1", "F_Value_1", "Arg_2", "S_Value_2", "F_Value_2", "Arg_3", "S_Value_3", "F_Value_3",
-		"Arg_4", "S_Value_4", "F_Value_4", "Arg_5", "S_Value_5", "F_Value_5"}
+	columns := []string{"Hash", "BlockNumber", "TxnIndex", "FromAddress", "ToAddress", "GasPrice", "Gas",
+		"Value", "ExactValue", "Nonce", "BlockTime", "Input", "Method", "Standard", "ArgsLen"}
+	return append(columns, paramColumns()...)
 }
 
 // implement pgx.CopyFromSource interface, return tuple of values in order of transactionColumns()
@@ -34,6 +32,7 @@ func (c *copyFromTransactions) Values() ([]interface{}, error) {
 	v = append(v, transaction.GasPrice)
 	v = append(v, transaction.Gas)
 	v = append(v, common.BigIntToFloat(transaction.Value))
+	v = append(v, common.BigIntToString(transaction.Value))
 	v = append(v, transaction.Nonce)
 	v = append(v, common.SecondsToDateTime(transaction.BlockTime))
 	if len(transaction.Params) > 0 && len(transaction.Params) <= 5 {
@@ -42,19 +41,9 @@ func (c *copyFromTransactions) Values() ([]interface{}, error) {
 		v = append(v, filterBytesByLength(transaction.Input, 16384))
 	}
 	v = append(v, truncateString(transaction.Method, 256))
+	v = append(v, truncateString(transaction.Standard, 16))
 	v = append(v, len(transaction.Params))
-	for i := 0; i < 5; i++ {
-		if i < len(transaction.Params) {
-			v = append(v, truncateString(transaction.Params[i].Name, 256))
-			s, f := convertNamedValue(transaction.Params[i])
-			v = append(v, truncateString(s, 4096))
-			v = append(v, f)
-		} else {
-			v = append(v, nil)
-			v = append(v, nil)
-			v = append(v, 0)
-		}
-	}
+	v = appendParamValues(v, transaction.Params)
 	//fmt.Println("Copy transaction", v[0])
 	return v, nil
 }
@@ -112,14 +101,5 @@ func writeTransactionsToS3(blocks map[string]*common.Block, s3Folder string) (in
 			source.rows = append(source.rows, v)
 		}
 	}
-	data, err := composeCSVData(source)
-	if err != nil {
-		return txCount, err
-	}
-
-	//fmt.Println("Write transactions to s3:", string(data))
-	s3Filename := fmt.Sprintf("%s/transactions.csv", s3Folder)
-	_, err = writeS3File(s3Filename, data)
-
-	return txCount, err
+	return txCount, writeStagedRows(s3Folder, blockTable("transactions"), source)
 }
@@ -0,0 +1,77 @@
+package redshift
+
+import (
+	"github.com/open-dovetail/eth-track/common"
+)
+
+type copyFromUserOperations struct {
+	rows []*common.UserOperation
+	idx  int
+}
+
+// column names for batch insert or copy
+func userOperationColumns() []string {
+	columns := []string{"TxnHash", "OpIndex", "BlockNumber", "EntryPoint", "Sender", "Nonce", "Factory", "Paymaster", "CallData",
+		"Method", "Standard", "ArgsLen"}
+	columns = append(columns, paramColumns()...)
+	return append(columns, "UserOpHash", "Success", "ActualGasCost", "ActualGasUsed", "BlockTime")
+}
+
+// implement pgx.CopyFromSource interface, return tuple of values in order of userOperationColumns()
+func (c *copyFromUserOperations) Values() ([]interface{}, error) {
+	op := c.rows[c.idx]
+	var v []interface{}
+	v = append(v, common.HexToFixedString(op.TxnHash, 64))
+	v = append(v, op.OpIndex)
+	v = append(v, op.BlockNumber)
+	v = append(v, common.HexToFixedString(op.EntryPoint, 40))
+	v = append(v, common.HexToFixedString(op.Sender, 40))
+	nonce := "0"
+	if op.Nonce != nil {
+		nonce = op.Nonce.String()
+	}
+	v = append(v, nonce)
+	v = append(v, common.HexToFixedString(op.Factory, 40))
+	v = append(v, common.HexToFixedString(op.Paymaster, 40))
+	if len(op.Params) > 0 && len(op.Params) <= 5 {
+		v = append(v, []byte{})
+	} else {
+		v = append(v, filterBytesByLength(op.CallData, 16384))
+	}
+	v = append(v, truncateString(op.Method, 256))
+	v = append(v, truncateString(op.Standard, 16))
+	v = append(v, len(op.Params))
+	v = appendParamValues(v, op.Params)
+	v = append(v, common.HexToFixedString(op.UserOpHash, 64))
+	v = append(v, op.Success)
+	v = append(v, common.BigIntToFloat(op.ActualGasCost))
+	v = append(v, op.ActualGasUsed)
+	v = append(v, common.SecondsToDateTime(op.BlockTime))
+	return v, nil
+}
+
+func (c *copyFromUserOperations) Next() bool {
+	c.idx++
+	return c.idx < len(c.rows)
+}
+
+func (c *copyFromUserOperations) Err() error {
+	return nil
+}
+
+// write ERC-4337 user operations of bundle transactions in specified blocks to s3 as a csv file.
+func writeUserOperationsToS3(blocks map[string]*common.Block, s3Folder string) (int, error) {
+	opCount := 0
+	if len(blocks) == 0 {
+		return opCount, nil
+	}
+
+	source := &copyFromUserOperations{idx: -1}
+	for _, b := range blocks {
+		for _, t := range b.Transactions {
+			opCount += len(t.UserOps)
+			source.rows = append(source.rows, t.UserOps...)
+		}
+	}
+	return opCount, writeStagedRows(s3Folder, blockTable("user_operations"), source)
+}
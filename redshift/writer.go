@@ -0,0 +1,190 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+)
+
+// block interval of a worker job and the s3 folder of its staged csv files
+type stagedJob struct {
+	folder   string
+	interval Interval
+}
+
+// accumulates staged jobs of multiple workers, and copies them to redshift in one db transaction
+// using manifest files, so redshift commits less frequently when many workers run concurrently.
+// progress of block intervals is advanced only after the combined commit.
+type BlockWriter struct {
+	sync.Mutex
+	jobs      []*stagedJob
+	batchJobs int // max number of staged jobs in a combined copy
+	flushLock sync.Mutex
+}
+
+// singleton block writer
+var blockWriter *BlockWriter
+
+// initialize or return the block writer that copies up to batchJobs staged jobs at once
+func GetBlockWriter(batchJobs int) *BlockWriter {
+	if blockWriter != nil {
+		return blockWriter
+	}
+	if batchJobs < 1 {
+		batchJobs = 1
+	}
+	blockWriter = &BlockWriter{batchJobs: batchJobs}
+	return blockWriter
+}
+
+// write data of blocks/transactions/events to s3 as csv files, which are copied to redshift later by a BlockWriter
+func StageBlocks(blocks map[string]*common.Block, s3Folder string) error {
+	if err := writeBlocksToS3(blocks, s3Folder); err != nil {
+		// delete partially staged files, since the job is not added to the block writer
+		deleteStagedFolder(s3Folder)
+		return err
+	}
+	return nil
+}
+
+// add a staged job, and copy all staged jobs to redshift if the number of jobs reaches the batch size
+func (w *BlockWriter) Add(s3Folder string, v Interval) error {
+	w.Lock()
+	w.jobs = append(w.jobs, &stagedJob{folder: s3Folder, interval: v})
+	full := len(w.jobs) >= w.batchJobs
+	w.Unlock()
+
+	if full {
+		return w.Flush()
+	}
+	return nil
+}
+
+// return the number of staged jobs that are not yet copied to redshift
+func (w *BlockWriter) Pending() int {
+	w.Lock()
+	defer w.Unlock()
+	return len(w.jobs)
+}
+
+// copy pending staged jobs to redshift before exit, which must be called after all workers stopped staging new jobs.
+// staged files of failed jobs are kept for inspection or replay, and their blocks are processed again after restart.
+func (w *BlockWriter) Close() error {
+	if n := w.Pending(); n > 0 {
+		glog.Infof("Copy %d pending staged jobs before exit", n)
+	}
+	return w.Flush()
+}
+
+// copy all staged jobs to redshift in a db transaction using manifest files,
+// then add blocks of the jobs to block cache and save progress.
+func (w *BlockWriter) Flush() error {
+	// only one combined copy at a time, while workers continue to stage new jobs
+	w.flushLock.Lock()
+	defer w.flushLock.Unlock()
+
+	w.Lock()
+	jobs := w.jobs
+	w.jobs = nil
+	w.Unlock()
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	startTime := time.Now().Unix()
+	manifestFolder := fmt.Sprintf("manifest/%d", time.Now().UnixNano())
+	if err := w.copyJobs(jobs, manifestFolder); err != nil {
+		// keep the manifest and staged folders, so the failed copy can be inspected or replayed
+		var folders []string
+		for _, job := range jobs {
+			folders = append(folders, job.folder)
+		}
+		glog.Warningf("Failed to copy staged jobs, kept manifest %s and staged folders %v", manifestFolder, folders)
+		return err
+	}
+	deleteStagedFolder(manifestFolder)
+	for _, job := range jobs {
+		deleteStagedFolder(job.folder)
+	}
+
+	blockCache, err := GetBlockCache()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		for i := job.interval.Low; i <= job.interval.High; i++ {
+			blockCache.AddBlock(i)
+		}
+	}
+	glog.Infof("Copied %d staged jobs - elapsed: %ds", len(jobs), (time.Now().Unix() - startTime))
+	return blockCache.SaveNextInterval()
+}
+
+// write manifest files of staged jobs, then copy and merge them in a db transaction
+func (w *BlockWriter) copyJobs(jobs []*stagedJob, manifestFolder string) error {
+	tables := blockTables()
+	for _, s := range tables {
+		data, err := composeManifest(objectStore, s, jobs)
+		if err != nil {
+			return err
+		}
+		if err := objectStore.WriteFile(manifestFolder+"/"+s.manifestName(), data); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := mergeStagedTables(tx, ctx, tables, manifestFolder, true); err != nil {
+		glog.Warningf("rollback copy %d staged jobs", len(jobs))
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// redshift manifest file that lists s3 files to copy
+type manifest struct {
+	Entries []manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	URL       string        `json:"url"`
+	Mandatory bool          `json:"mandatory"`
+	Meta      *manifestMeta `json:"meta,omitempty"`
+}
+
+// redshift requires content length of each Parquet file listed in a manifest
+type manifestMeta struct {
+	ContentLength int64 `json:"content_length"`
+}
+
+// compose manifest of the staged files of a table in the s3 folders of specified jobs
+func composeManifest(store ObjectStore, s *stagedTable, jobs []*stagedJob) ([]byte, error) {
+	m := manifest{}
+	for _, job := range jobs {
+		name := job.folder + "/" + s.fileName()
+		entry := manifestEntry{
+			URL:       store.URL(name),
+			Mandatory: true,
+		}
+		if s.parquet {
+			size, err := store.FileSize(name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to get size of staged file %s", name)
+			}
+			entry.Meta = &manifestMeta{ContentLength: size}
+		}
+		m.Entries = append(m.Entries, entry)
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,234 @@
+package redshift
+
+// Run all unit test: `go test -v`
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/pkg/errors"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeManifest(t *testing.T) {
+	jobs := []*stagedJob{
+		{folder: "14000039", interval: Interval{Low: 14000000, High: 14000039}},
+		{folder: "14000079", interval: Interval{Low: 14000040, High: 14000079}},
+	}
+	logs := &stagedTable{name: "logs", csvFile: "logs.csv", gzip: true}
+	data, err := composeManifest(&s3Bucket{name: "dev-eth-track"}, logs, jobs)
+	require.NoError(t, err, "compose manifest should not throw error")
+	assert.Equal(t, `{"entries":[{"url":"s3://dev-eth-track/14000039/logs.csv.gz","mandatory":true},{"url":"s3://dev-eth-track/14000079/logs.csv.gz","mandatory":true}]}`,
+		string(data), "manifest should list staged files of all jobs")
+	assert.Equal(t, "logs.manifest", logs.manifestName(), "name of manifest file")
+}
+
+func TestStageBlocksInLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err, "create local store should not throw error")
+	prevStore := GetObjectStore()
+	SetObjectStore(store)
+	SetStagingGzip(true)
+	defer func() {
+		SetObjectStore(prevStore)
+		SetStagingGzip(false)
+	}()
+
+	hash := "0x5a4cba0f0ab5ab8b1f0d2d5e2e6a0bb0c9e0f0e6c7f3e7e0d6a9c1b2e3f4a5b6"
+	blocks := map[string]*common.Block{hash: {
+		Hash:       hash,
+		Number:     14000000,
+		Miner:      "0x0000000000000000000000000000000000000001",
+		Difficulty: big.NewInt(1000),
+		BlockTime:  1640995200,
+		Transactions: map[string]*common.Transaction{"0x01": {
+			Hash:        "0x01",
+			BlockNumber: 14000000,
+			From:        "0x0000000000000000000000000000000000000002",
+			Value:       big.NewInt(1),
+			BlockTime:   1640995200,
+		}},
+	}}
+	require.NoError(t, StageBlocks(blocks, "14000000"), "stage blocks should not throw error")
+
+	for _, s := range blockTables() {
+		_, err := store.ReadFile("14000000/" + s.fileName())
+		assert.NoError(t, err, "staged file of %s should exist", s.name)
+	}
+	f, err := os.Open(filepath.Join(store.(*localStore).root, "14000000", "blocks.csv.gz"))
+	require.NoError(t, err, "open staged blocks should not throw error")
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err, "staged blocks should be compressed")
+	data, err := ioutil.ReadAll(zr)
+	require.NoError(t, err, "read staged blocks should not throw error")
+	assert.Contains(t, string(data), "14000000", "staged blocks should contain block number")
+
+	deleteStagedFolder("14000000")
+	_, err = store.ReadFile("14000000/blocks.csv.gz")
+	assert.Error(t, err, "staged folder should be deleted")
+}
+
+// object store that fails to write files of a name suffix
+type failingStore struct {
+	ObjectStore
+	suffix string
+}
+
+func (s *failingStore) WriteFile(name string, content []byte) error {
+	if strings.HasSuffix(name, s.suffix) {
+		return errors.Errorf("failed to write %s", name)
+	}
+	return s.ObjectStore.WriteFile(name, content)
+}
+
+func TestStageBlocksFailure(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err, "create local store should not throw error")
+	prevStore := GetObjectStore()
+	SetObjectStore(&failingStore{ObjectStore: store, suffix: "blocks.csv"})
+	t.Cleanup(func() { SetObjectStore(prevStore) })
+
+	hash := "0x5a4cba0f0ab5ab8b1f0d2d5e2e6a0bb0c9e0f0e6c7f3e7e0d6a9c1b2e3f4a5b6"
+	blocks := map[string]*common.Block{hash: {
+		Hash:       hash,
+		Number:     14000000,
+		Difficulty: big.NewInt(1000),
+		BlockTime:  1640995200,
+		Transactions: map[string]*common.Transaction{"0x01": {
+			Hash:        "0x01",
+			BlockNumber: 14000000,
+			Value:       big.NewInt(1),
+			BlockTime:   1640995200,
+		}},
+	}}
+	assert.Error(t, StageBlocks(blocks, "14000000"), "stage blocks should fail")
+	_, err = store.ReadFile("14000000/transactions.csv")
+	assert.Error(t, err, "partially staged folder should be deleted")
+}
+
+func TestStageBlocksAsParquet(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err, "create local store should not throw error")
+	prevStore := GetObjectStore()
+	SetObjectStore(store)
+	SetStagingParquet(true)
+	defer func() {
+		SetObjectStore(prevStore)
+		SetStagingParquet(false)
+	}()
+
+	hash := "0x5a4cba0f0ab5ab8b1f0d2d5e2e6a0bb0c9e0f0e6c7f3e7e0d6a9c1b2e3f4a5b6"
+	txHash := "0x9c1b2e3f4a5b65a4cba0f0ab5ab8b1f0d2d5e2e6a0bb0c9e0f0e6c7f3e7e0d6a"
+	blocks := map[string]*common.Block{hash: {
+		Hash:       hash,
+		Number:     14000000,
+		Miner:      "0x0000000000000000000000000000000000000001",
+		Difficulty: big.NewInt(1000),
+		BlockTime:  1640995200,
+		Transactions: map[string]*common.Transaction{txHash: {
+			Hash:        txHash,
+			BlockNumber: 14000000,
+			From:        "0x0000000000000000000000000000000000000002",
+			Value:       big.NewInt(1),
+			BlockTime:   1640995200,
+		}},
+		Logs: map[uint64]*common.EventLog{1: {
+			BlockNumber: 14000000,
+			LogIndex:    1,
+			TxnHash:     txHash,
+			Address:     "0x0000000000000000000000000000000000000003",
+			Event:       "Transfer",
+			Standard:    "ERC20",
+			Params: []*common.NamedValue{
+				{Name: "from", Kind: abi.KindAddress, Value: web3.HexToAddress("0x0000000000000000000000000000000000000002")},
+				{Name: "value", Kind: abi.KindUInt, Value: big.NewInt(2500)},
+			},
+			Amount:    0.25,
+			BlockTime: 1640995200,
+		}},
+	}}
+	require.NoError(t, StageBlocks(blocks, "14000000"), "stage blocks should not throw error")
+
+	for _, s := range blockTables() {
+		assert.True(t, strings.HasSuffix(s.fileName(), ".parquet"), "staged file of %s should be Parquet", s.name)
+		_, err := store.ReadFile("14000000/" + s.fileName())
+		assert.NoError(t, err, "staged file of %s should exist", s.name)
+	}
+
+	rows := readParquetRows(t, store, "14000000/blocks.parquet")
+	require.Equal(t, 1, len(rows), "staged blocks should contain 1 row")
+	assert.Contains(t, rows[0], `"Number":14000000`, "staged blocks should contain block number")
+	assert.Contains(t, rows[0], `"BlockTime":1640995200000000`, "block time should be staged in micros")
+
+	rows = readParquetRows(t, store, "14000000/logs.parquet")
+	require.Equal(t, 1, len(rows), "staged logs should contain 1 row")
+	assert.Contains(t, rows[0], `"Event":"Transfer"`, "staged logs should contain event name")
+	assert.Contains(t, rows[0], `"Amount":0.25`, "staged logs should contain token amount")
+	assert.Contains(t, rows[0], `"E_Value_2":"2500"`, "staged logs should contain exact value of uint param")
+}
+
+// read rows of a staged Parquet file as JSON strings
+func readParquetRows(t *testing.T, store ObjectStore, name string) []string {
+	data, err := store.ReadFile(name)
+	require.NoError(t, err, "read staged file %s should not throw error", name)
+	file, err := buffer.NewBufferFile(data)
+	require.NoError(t, err, "open parquet buffer should not throw error")
+	pr, err := reader.NewParquetReader(file, nil, 1)
+	require.NoError(t, err, "create parquet reader should not throw error")
+	defer pr.ReadStop()
+	values, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	require.NoError(t, err, "read staged rows should not throw error")
+	var rows []string
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		require.NoError(t, err, "marshal staged row should not throw error")
+		rows = append(rows, string(data))
+	}
+	return rows
+}
+
+func TestParquetManifest(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err, "create local store should not throw error")
+	require.NoError(t, store.WriteFile("14000039/logs.parquet", []byte("PAR1")), "write staged file should not throw error")
+
+	jobs := []*stagedJob{{folder: "14000039", interval: Interval{Low: 14000000, High: 14000039}}}
+	logs := &stagedTable{name: "logs", csvFile: "logs.csv", parquet: true}
+	data, err := composeManifest(store, logs, jobs)
+	require.NoError(t, err, "compose manifest should not throw error")
+	assert.Contains(t, string(data), `"meta":{"content_length":4}`, "manifest of Parquet files should contain content length")
+
+	jobs = append(jobs, &stagedJob{folder: "14000079", interval: Interval{Low: 14000040, High: 14000079}})
+	_, err = composeManifest(store, logs, jobs)
+	assert.Error(t, err, "manifest should fail if a staged Parquet file is missing")
+}
+
+func TestParquetMetadata(t *testing.T) {
+	for _, s := range blockTables() {
+		_, err := parquetMetadata(s.name, s.columns)
+		assert.NoError(t, err, "all columns of %s should have Parquet types", s.name)
+	}
+	md, err := parquetMetadata("blocks", []string{"Hash", "Number", "BlockTime"})
+	require.NoError(t, err, "parquet metadata of blocks should not throw error")
+	assert.Equal(t, []string{
+		"name=Hash, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=Number, type=INT64, repetitiontype=OPTIONAL",
+		"name=BlockTime, type=INT64, convertedtype=TIMESTAMP_MICROS, repetitiontype=OPTIONAL",
+	}, md, "parquet schema of block columns")
+	_, err = parquetMetadata("unknown", []string{"Hash"})
+	assert.Error(t, err, "parquet metadata of undefined table should throw error")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/open-dovetail/eth-track/common"
 	"github.com/pkg/errors"
 	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
 )
 
 type ClickHouseConnection struct {
@@ -39,6 +40,47 @@ type ParamsValuer struct {
 	Seq         driver.Valuer
 	ValueString driver.Valuer
 	ValueDouble driver.Valuer
+	// exact values of unsigned or signed integer params, 0 for other params
+	ValueUInt256 driver.Valuer
+	ValueInt256  driver.Valuer
+}
+
+// policy to keep raw data, i.e., input of transactions, or topics and data of event logs
+type RawDataPolicy int8
+
+const (
+	RawAlways     RawDataPolicy = iota // keep raw data of all rows
+	RawUnknown                         // keep raw data only if decoding failed
+	RawManyParams                      // keep raw data if decoding failed, or more than 5 params are decoded
+)
+
+var (
+	rawLogPolicy   = RawAlways
+	rawInputPolicy = RawManyParams
+)
+
+// set policy to keep raw topics and data of event logs
+func SetRawLogPolicy(policy RawDataPolicy) {
+	rawLogPolicy = policy
+}
+
+// set policy to keep raw input of transactions
+func SetRawInputPolicy(policy RawDataPolicy) {
+	rawInputPolicy = policy
+}
+
+// return true if the policy keeps raw data of a row of the decoded method or event name and number of params
+func (p RawDataPolicy) keeps(name string, params int) bool {
+	switch p {
+	case RawAlways:
+		return true
+	case RawManyParams:
+		if params > 5 {
+			return true
+		}
+	}
+	// decoding failed, or not decoded, e.g., unverified contract
+	return len(name) == 0 || name == "UNKNOWN"
 }
 
 // singleton
@@ -308,7 +350,9 @@ func QueryContracts(recentDays int) (*sql.Rows, error) {
 	glog.Infof("query contracts used in recent %d days", recentDays)
 	evtDt := time.Now().Add(time.Duration(-recentDays*24) * time.Hour)
 	sql := fmt.Sprintf(`SELECT
-			Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI
+			Address, Name, Symbol, Decimals, TotalSupply, LastEventDate, LastErrorDate, ABI,
+			ContractName, Compiler, Optimized, OptimizerRuns, License, IsProxy, Implementation, AddressType, CodeHash, CloneTarget,
+			TokenStandard
 		FROM contracts
 		WHERE LastEventDate > '%s'`, evtDt.Format("2006-01-02"))
 	rows, err := db.Query(sql)
@@ -331,7 +375,18 @@ func QueryContract(address string) (*common.Contract, error) {
 			TotalSupply,
 			LastEventDate,
 			LastErrorDate,
-			ABI
+			ABI,
+			ContractName,
+			Compiler,
+			Optimized,
+			OptimizerRuns,
+			License,
+			IsProxy,
+			Implementation,
+			AddressType,
+			CodeHash,
+			CloneTarget,
+			TokenStandard
 		FROM contracts
 		WHERE Address = ?`, address[2:])
 
@@ -351,6 +406,9 @@ func QueryContract(address string) (*common.Contract, error) {
 		// Note: parser timezone can be overriden in request URL with parameter, e.g. location=UTC,
 		//       which would set time location to time.LoadLocation(loc) - ref go-clickhouse/config.go
 		var lastEventDate, lastErrorDate time.Time
+		var optimized, isProxy uint8
+		var implementation, codeHash, cloneTarget string
+		var addressType int8
 
 		if err := rows.Scan(
 			&contract.Name,
@@ -360,12 +418,33 @@ func QueryContract(address string) (*common.Contract, error) {
 			&lastEventDate,
 			&lastErrorDate,
 			&contract.ABI,
+			&contract.ContractName,
+			&contract.Compiler,
+			&optimized,
+			&contract.OptimizerRuns,
+			&contract.License,
+			&isProxy,
+			&implementation,
+			&addressType,
+			&codeHash,
+			&cloneTarget,
+			&contract.TokenStandard,
 		); err != nil {
 			return nil, errors.Wrapf(err, "Failed to parse query result for %s", address)
 		}
 
 		contract.LastEventDate = lastEventDate.Unix()
 		contract.LastErrorDate = lastErrorDate.Unix()
+		contract.Optimized = optimized > 0
+		contract.IsProxy = isProxy > 0
+		contract.AddressType = common.AddressType(addressType)
+		contract.CodeHash = strings.Trim(codeHash, "\x00")
+		if cloneTarget = strings.Trim(cloneTarget, "\x00"); len(cloneTarget) > 0 {
+			contract.CloneTarget = "0x" + cloneTarget
+		}
+		if implementation = strings.Trim(implementation, "\x00"); len(implementation) > 0 {
+			contract.Implementation = "0x" + implementation
+		}
 		if glog.V(2) {
 			glog.Infoln("Query contract", contract.Address, contract.Symbol, contract.TotalSupply, contract.LastEventDate)
 			glog.Infoln("contract ABI", contract.ABI)
@@ -401,9 +480,18 @@ func (c *ClickHouseConnection) startTx() (*ClickHouseTransaction, error) {
 	if err := txn.prepareLogStmt(); err != nil {
 		return nil, err
 	}
+	if err := txn.prepareCallStmt(); err != nil {
+		return nil, err
+	}
+	if err := txn.prepareUserOperationStmt(); err != nil {
+		return nil, err
+	}
 	if err := txn.prepareProgressStmt(); err != nil {
 		return nil, err
 	}
+	if err := txn.prepareTokenSupplyStmt(); err != nil {
+		return nil, err
+	}
 	return txn, nil
 }
 
@@ -478,6 +566,45 @@ func (t *ClickHouseTransaction) InsertProgress(progress *common.Progress) error
 	return err
 }
 
+func (t *ClickHouseTransaction) prepareTokenSupplyStmt() error {
+	if _, ok := t.stmts["tokenSupply"]; !ok {
+		stmt, err := t.tx.Prepare(`
+			INSERT INTO token_supply (
+				Address,
+				TotalSupply,
+				ExactSupply,
+				BlockNumber,
+				SnapshotTime
+			) VALUES (
+				?, ?, ?, ?, ?
+			)`)
+		if err != nil {
+			return err
+		}
+		t.stmts["tokenSupply"] = stmt
+	}
+	return nil
+}
+
+func (t *ClickHouseTransaction) InsertTokenSupply(supply *common.TokenSupply) error {
+	txnLock.Lock()
+	defer txnLock.Unlock()
+
+	stmt, ok := t.stmts["tokenSupply"]
+	if !ok {
+		return errors.New("token supply statement is not prepared for ClickHouse transaction")
+	}
+
+	_, err := stmt.Exec(
+		hexToFixedString(supply.Address, 40),
+		supply.TotalSupply,
+		bigIntToDecimal(supply.ExactSupply),
+		clickhouse.UInt64(supply.BlockNumber),
+		secondsToDateTime(supply.SnapshotTime),
+	)
+	return err
+}
+
 func (t *ClickHouseTransaction) prepareContractStmt() error {
 	if _, ok := t.stmts["contract"]; !ok {
 		stmt, err := t.tx.Prepare(`
@@ -489,9 +616,21 @@ func (t *ClickHouseTransaction) prepareContractStmt() error {
 				TotalSupply,
 				LastEventDate,
 				LastErrorDate,
-				ABI
+				ABI,
+				ContractName,
+				Compiler,
+				Optimized,
+				OptimizerRuns,
+				License,
+				IsProxy,
+				Implementation,
+				AddressType,
+				CodeHash,
+				CloneTarget,
+				TokenStandard,
+				ExactSupply
 			) VALUES (
-				?, ?, ?, ?, ?, ?, ?, ?
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 			)`)
 		if err != nil {
 			return err
@@ -519,6 +658,18 @@ func (t *ClickHouseTransaction) InsertContract(contract *common.Contract) error
 		clickhouse.Date(secondsToDateTime(contract.LastEventDate)),
 		clickhouse.Date(secondsToDateTime(contract.LastErrorDate)),
 		contract.ABI,
+		contract.ContractName,
+		contract.Compiler,
+		boolToUInt8(contract.Optimized),
+		uint32(contract.OptimizerRuns),
+		contract.License,
+		boolToUInt8(contract.IsProxy),
+		hexToFixedString(contract.Implementation, 40),
+		int8(contract.AddressType),
+		contract.CodeHash,
+		hexToFixedString(contract.CloneTarget, 40),
+		contract.TokenStandard,
+		bigIntToDecimal(contract.ExactSupply),
 	)
 	return err
 }
@@ -532,12 +683,13 @@ func (t *ClickHouseTransaction) prepareBlockStmt() error {
 				ParentHash,
 				Miner,
 				Difficulty,
+				ExactDifficulty,
 				GasLimit,
 				GasUsed,
 				Status,
 				BlockTime
 			) VALUES (
-				?, ?, ?, ?, ?, ?, ?, ?, ?
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 			)`)
 		if err != nil {
 			return err
@@ -566,6 +718,7 @@ func (t *ClickHouseTransaction) InsertBlock(block *common.Block) error {
 		hexToFixedString(block.ParentHash.String(), 64),
 		hexToFixedString(block.Miner, 40),
 		bigIntToFloat(block.Difficulty),
+		bigIntToDecimal(block.Difficulty),
 		clickhouse.UInt64(block.GasLimit),
 		clickhouse.UInt64(block.GasUsed),
 		status,
@@ -585,17 +738,22 @@ func (t *ClickHouseTransaction) prepareTransactionStmt() error {
 				From,
 				To,
 				Method,
+				Standard,
 				Params.Name,
 				Params.Seq,
 				Params.ValueString,
 				Params.ValueDouble,
+				Params.ValueUInt256,
+				Params.ValueInt256,
 				GasPrice,
 				Gas,
 				Value,
+				ExactValue,
 				Nonce,
+				Input,
 				BlockTime
 			) VALUES (
-				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 			)`)
 		if err != nil {
 			return err
@@ -619,6 +777,10 @@ func (t *ClickHouseTransaction) InsertTransaction(transaction *common.Transactio
 	if transaction.Status {
 		status = 1
 	}
+	var input string
+	if rawInputPolicy.keeps(transaction.Method, len(transaction.Params)) {
+		input = hex.EncodeToString(transaction.Input)
+	}
 	_, err := stmt.Exec(
 		hexToFixedString(transaction.Hash, 64),
 		clickhouse.UInt64(transaction.BlockNumber),
@@ -627,16 +789,168 @@ func (t *ClickHouseTransaction) InsertTransaction(transaction *common.Transactio
 		hexToFixedString(transaction.From, 40),
 		hexToFixedString(transaction.To, 40),
 		transaction.Method,
+		transaction.Standard,
 		params.Name,
 		params.Seq,
 		params.ValueString,
 		params.ValueDouble,
+		params.ValueUInt256,
+		params.ValueInt256,
 		clickhouse.UInt64(transaction.GasPrice),
 		clickhouse.UInt64(transaction.Gas),
 		bigIntToFloat(transaction.Value),
+		bigIntToDecimal(transaction.Value),
 		clickhouse.UInt64(transaction.Nonce),
+		input,
 		secondsToDateTime(transaction.BlockTime),
 	)
+	if err != nil {
+		return err
+	}
+	return t.insertTypedTransaction(transaction)
+}
+
+func (t *ClickHouseTransaction) prepareCallStmt() error {
+	if _, ok := t.stmts["call"]; !ok {
+		stmt, err := t.tx.Prepare(`
+			INSERT INTO calls (
+				TxnHash,
+				Path,
+				BlockNumber,
+				From,
+				To,
+				Value,
+				ExactValue,
+				Method,
+				Standard,
+				Params.Name,
+				Params.Seq,
+				Params.ValueString,
+				Params.ValueDouble,
+				Params.ValueUInt256,
+				Params.ValueInt256,
+				BlockTime
+			) VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			)`)
+		if err != nil {
+			return err
+		}
+		t.stmts["call"] = stmt
+	}
+	return nil
+}
+
+// insert an inner call of a batch transaction
+func (t *ClickHouseTransaction) InsertCall(call *common.InnerCall) error {
+	txnLock.Lock()
+	defer txnLock.Unlock()
+
+	stmt, ok := t.stmts["call"]
+	if !ok {
+		return errors.New("call statement is not prepared for ClickHouse transaction")
+	}
+
+	params := paramsToValuers(call.Params)
+	_, err := stmt.Exec(
+		hexToFixedString(call.TxnHash, 64),
+		call.Path,
+		clickhouse.UInt64(call.BlockNumber),
+		hexToFixedString(call.From, 40),
+		hexToFixedString(call.To, 40),
+		bigIntToFloat(call.Value),
+		bigIntToDecimal(call.Value),
+		call.Method,
+		call.Standard,
+		params.Name,
+		params.Seq,
+		params.ValueString,
+		params.ValueDouble,
+		params.ValueUInt256,
+		params.ValueInt256,
+		secondsToDateTime(call.BlockTime),
+	)
+	return err
+}
+
+func (t *ClickHouseTransaction) prepareUserOperationStmt() error {
+	if _, ok := t.stmts["userop"]; !ok {
+		stmt, err := t.tx.Prepare(`
+			INSERT INTO user_operations (
+				TxnHash,
+				OpIndex,
+				BlockNumber,
+				EntryPoint,
+				Sender,
+				Nonce,
+				Factory,
+				Paymaster,
+				Method,
+				Standard,
+				Params.Name,
+				Params.Seq,
+				Params.ValueString,
+				Params.ValueDouble,
+				Params.ValueUInt256,
+				Params.ValueInt256,
+				UserOpHash,
+				Success,
+				ActualGasCost,
+				ActualGasUsed,
+				BlockTime
+			) VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			)`)
+		if err != nil {
+			return err
+		}
+		t.stmts["userop"] = stmt
+	}
+	return nil
+}
+
+// insert an ERC-4337 user operation of a bundle transaction
+func (t *ClickHouseTransaction) InsertUserOperation(op *common.UserOperation) error {
+	txnLock.Lock()
+	defer txnLock.Unlock()
+
+	stmt, ok := t.stmts["userop"]
+	if !ok {
+		return errors.New("user operation statement is not prepared for ClickHouse transaction")
+	}
+
+	nonce := "0"
+	if op.Nonce != nil {
+		nonce = op.Nonce.String()
+	}
+	var success = int8(0)
+	if op.Success {
+		success = 1
+	}
+	params := paramsToValuers(op.Params)
+	_, err := stmt.Exec(
+		hexToFixedString(op.TxnHash, 64),
+		uint32(op.OpIndex),
+		clickhouse.UInt64(op.BlockNumber),
+		hexToFixedString(op.EntryPoint, 40),
+		hexToFixedString(op.Sender, 40),
+		nonce,
+		hexToFixedString(op.Factory, 40),
+		hexToFixedString(op.Paymaster, 40),
+		op.Method,
+		op.Standard,
+		params.Name,
+		params.Seq,
+		params.ValueString,
+		params.ValueDouble,
+		params.ValueUInt256,
+		params.ValueInt256,
+		hexToFixedString(op.UserOpHash, 64),
+		success,
+		bigIntToFloat(op.ActualGasCost),
+		clickhouse.UInt64(op.ActualGasUsed),
+		secondsToDateTime(op.BlockTime),
+	)
 	return err
 }
 
@@ -649,11 +963,11 @@ func RejectTransactions(to, hash []string) error {
 	hashList := strings.Join(hash, "','")
 	sql := fmt.Sprintf(`
 		INSERT INTO transactions (Hash, BlockNumber, TxnIndex, Status, From, To, 
-			Method, Params.Name, Params.Seq, Params.ValueString, Params.ValueDouble,
-			GasPrice, Gas, Value, Nonce, BlockTime
+			Method, Standard, Params.Name, Params.Seq, Params.ValueString, Params.ValueDouble,
+			Params.ValueUInt256, Params.ValueInt256, GasPrice, Gas, Value, ExactValue, Nonce, Input, BlockTime
 		) SELECT Hash, BlockNumber, TxnIndex, -1, From, To,
-			Method, Params.Name, Params.Seq, Params.ValueString, Params.ValueDouble,
-			GasPrice, Gas, Value, Nonce, BlockTime
+			Method, Standard, Params.Name, Params.Seq, Params.ValueString, Params.ValueDouble,
+			Params.ValueUInt256, Params.ValueInt256, GasPrice, Gas, Value, ExactValue, Nonce, Input, BlockTime
 		FROM transactions
 		WHERE To IN ('%s') AND Hash IN ('%s')`, toList, hashList)
 
@@ -682,14 +996,20 @@ func (t *ClickHouseTransaction) prepareLogStmt() error {
 				TxnIndex,
 				TxnHash,
 				Address,
+				Topics,
+				Data,
 				Event,
+				Standard,
+				Amount,
 				Params.Name,
 				Params.Seq,
 				Params.ValueString,
 				Params.ValueDouble,
+				Params.ValueUInt256,
+				Params.ValueInt256,
 				BlockTime
 			) VALUES (
-				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 			)`)
 		if err != nil {
 			return err
@@ -699,6 +1019,19 @@ func (t *ClickHouseTransaction) prepareLogStmt() error {
 	return nil
 }
 
+// return hex of raw topics and data of an event log, or empty values if they are not kept by rawLogPolicy
+func rawLogData(eventlog *common.EventLog) ([]string, string) {
+	topics := []string{}
+	var data string
+	if rawLogPolicy.keeps(eventlog.Event, len(eventlog.Params)) {
+		for _, topic := range eventlog.Topics {
+			topics = append(topics, hexToFixedString(topic, 64))
+		}
+		data = hex.EncodeToString(eventlog.Data)
+	}
+	return topics, data
+}
+
 func (t *ClickHouseTransaction) InsertLog(eventlog *common.EventLog) error {
 	txnLock.Lock()
 	defer txnLock.Unlock()
@@ -713,6 +1046,7 @@ func (t *ClickHouseTransaction) InsertLog(eventlog *common.EventLog) error {
 	if eventlog.Removed {
 		removed = 1
 	}
+	topics, data := rawLogData(eventlog)
 	_, err := stmt.Exec(
 		clickhouse.UInt64(eventlog.BlockNumber),
 		clickhouse.UInt64(eventlog.LogIndex),
@@ -720,14 +1054,23 @@ func (t *ClickHouseTransaction) InsertLog(eventlog *common.EventLog) error {
 		clickhouse.UInt64(eventlog.TxnIndex),
 		hexToFixedString(eventlog.TxnHash, 64),
 		hexToFixedString(eventlog.Address, 40),
+		clickhouse.Array(topics),
+		data,
 		eventlog.Event,
+		eventlog.Standard,
+		eventlog.Amount,
 		params.Name,
 		params.Seq,
 		params.ValueString,
 		params.ValueDouble,
+		params.ValueUInt256,
+		params.ValueInt256,
 		secondsToDateTime(eventlog.BlockTime),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return t.insertTypedLog(eventlog)
 }
 
 // convert Unix seconds to UTC time
@@ -735,6 +1078,13 @@ func secondsToDateTime(t int64) time.Time {
 	return time.Unix(t, 0).UTC()
 }
 
+func boolToUInt8(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // zero out time from DateTime, then return Unix seconds
 func timeToDate(t time.Time) int64 {
 	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
@@ -751,6 +1101,15 @@ func bigIntToFloat(i *big.Int) float64 {
 	return v
 }
 
+// return exact decimal text of a big integer for UInt256 or Int256 columns.
+// the driver does not quote []byte values in the interpolated sql.
+func bigIntToDecimal(i *big.Int) []byte {
+	if i == nil {
+		return []byte("0")
+	}
+	return []byte(i.String())
+}
+
 func floatToBigInt(f float64) *big.Int {
 	bf := big.NewFloat(f)
 	i, _ := bf.Int(nil)
@@ -773,19 +1132,32 @@ func hexToFixedString(h string, s int) string {
 func paramsToValuers(params []*common.NamedValue) *ParamsValuer {
 	if params == nil || len(params) == 0 {
 		return &ParamsValuer{
-			Name:        nil,
-			Seq:         nil,
-			ValueString: nil,
-			ValueDouble: nil,
+			Name:         nil,
+			Seq:          nil,
+			ValueString:  nil,
+			ValueDouble:  nil,
+			ValueUInt256: nil,
+			ValueInt256:  nil,
 		}
 	}
 	names := make([]string, len(params))
 	seqs := make([]int8, len(params))
 	stringValues := make([]string, len(params))
 	doubleValues := make([]float64, len(params))
+	uintValues := make([][]byte, len(params))
+	intValues := make([][]byte, len(params))
 	for i, v := range params {
 		names[i] = v.Name
 		seqs[i] = int8(i)
+		uintValues[i] = []byte("0")
+		intValues[i] = []byte("0")
+		if d, ok := common.ParamToDecimal(v); ok {
+			if v.Kind == abi.KindUInt {
+				uintValues[i] = []byte(d)
+			} else {
+				intValues[i] = []byte(d)
+			}
+		}
 
 		value := v.Value
 		if v.Kind.String() != "Bytes" {
@@ -822,10 +1194,12 @@ func paramsToValuers(params []*common.NamedValue) *ParamsValuer {
 		}
 	}
 	return &ParamsValuer{
-		Name:        clickhouse.Array(names),
-		Seq:         clickhouse.Array(seqs),
-		ValueString: clickhouse.Array(stringValues),
-		ValueDouble: clickhouse.Array(doubleValues),
+		Name:         clickhouse.Array(names),
+		Seq:          clickhouse.Array(seqs),
+		ValueString:  clickhouse.Array(stringValues),
+		ValueDouble:  clickhouse.Array(doubleValues),
+		ValueUInt256: clickhouse.Array(uintValues),
+		ValueInt256:  clickhouse.Array(intValues),
 	}
 }
 
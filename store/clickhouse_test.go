@@ -13,6 +13,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
 )
 
 func setup() error {
@@ -85,6 +87,12 @@ func TestContractStore(t *testing.T) {
 		LastEventDate: 1638850281,
 		LastErrorDate: 0,
 		ABI:           abiCode,
+		ContractName:  "Dai",
+		Compiler:      "v0.5.12+commit.7709ece9",
+		Optimized:     true,
+		OptimizerRuns: 200,
+		License:       "AGPL-3.0",
+		TokenStandard: "ERC20",
 	}
 	tx, err := GetDBTx()
 	require.NoError(t, err, "Start DB Tx should not throw exception")
@@ -110,6 +118,12 @@ func TestContractStore(t *testing.T) {
 	utcTime := secondsToDateTime(1638850281)
 	assert.Equal(t, timeToDate(utcTime), c.LastEventDate, "query result does not match lastEventDate")
 	assert.NotEmpty(t, c.ABI, "query result ABI should not be empty")
+	assert.Equal(t, "Dai", c.ContractName, "query result does not match contract name")
+	assert.True(t, c.Optimized, "query result does not match optimizer setting")
+	assert.Equal(t, 200, c.OptimizerRuns, "query result does not match optimizer runs")
+	assert.False(t, c.IsProxy, "query result does not match proxy flag")
+	assert.Empty(t, c.Implementation, "query result implementation should be empty")
+	assert.Equal(t, "ERC20", c.TokenStandard, "query result does not match token standard")
 }
 
 func TestProgressStore(t *testing.T) {
@@ -171,3 +185,81 @@ func TestBlockQuery(t *testing.T) {
 		assert.Equal(t, 66, len(earliestBlock.Hash), "block hash should be 66 characters long")
 	}
 }
+
+func TestTypedParams(t *testing.T) {
+	SetTypedTables([]string{"Transfer(address,address,uint256)", "0xC02AAA39B223FE8D0A0E5C4F27EAD9083C756CC2"})
+	defer SetTypedTables(nil)
+	assert.True(t, isTypedTarget("0x0000000000000000000000000000000000000001", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"), "Transfer event should be typed")
+	assert.True(t, isTypedTarget("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "0xa9059cbb"), "configured contract should be typed")
+	assert.False(t, isTypedTarget("0x0000000000000000000000000000000000000001", "0xa9059cbb"), "transfer method should not be typed")
+
+	transfer := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	assert.Equal(t, "Transfer_ddf252ad", typedTableName(transfer, "Transfer"), "table of event should be named by signature")
+	assert.Equal(t, "tx_transfer_a9059cbb", "tx_"+typedTableName("0xa9059cbb", "transfer"), "table of method should be named by signature")
+
+	// WETH Transfer(src, dst, wad) is stored in columns of ERC20 param names
+	columns, values := typedParams(typedLogColumns, transfer, []*common.NamedValue{
+		{Name: "src", Kind: abi.KindAddress, Value: web3.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d")},
+		{Name: "dst", Kind: abi.KindAddress, Value: web3.ZeroAddress},
+		{Name: "wad", Kind: abi.KindUInt, Value: big.NewInt(1000)},
+	})
+	require.Equal(t, 3, len(columns), "Transfer should contain 3 columns")
+	assert.Equal(t, typedColumn{"from", "FixedString(40)"}, columns[0], "column of address param")
+	assert.Equal(t, "7a250d5630b4cf539739df2c5dacb4c659f2488d", values[0], "value of address param")
+	assert.Equal(t, typedColumn{"value", "UInt256"}, columns[2], "column of uint256 param")
+	assert.Equal(t, []byte("1000"), values[2], "value of uint256 param")
+
+	columns, _ = typedParams(typedLogColumns, "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67", []*common.NamedValue{
+		{Name: "sender", Kind: abi.KindAddress, Value: web3.ZeroAddress},
+		{Name: "path", Kind: abi.KindArray, Value: []web3.Address{web3.ZeroAddress}},
+		{Name: "path[0]", Kind: abi.KindAddress, Value: web3.ZeroAddress},
+		{Name: "", Kind: abi.KindUInt, Value: uint8(18)},
+	})
+	require.Equal(t, 3, len(columns), "flattened param should be skipped")
+	assert.Equal(t, typedColumn{"arg0", "FixedString(40)"}, columns[0], "non-standard param should be named by position")
+	assert.Equal(t, typedColumn{"arg2", "UInt64"}, columns[2], "column of uint8 param")
+}
+
+func TestRawDataPolicy(t *testing.T) {
+	assert.True(t, RawAlways.keeps("transfer", 2), "RawAlways should keep decoded row")
+	for _, p := range []RawDataPolicy{RawUnknown, RawManyParams} {
+		assert.False(t, p.keeps("transfer", 2), "policy %d should not keep decoded row of few params", p)
+		assert.True(t, p.keeps("UNKNOWN", 0), "policy %d should keep row failed to decode", p)
+		assert.True(t, p.keeps("", 0), "policy %d should keep row not decoded", p)
+	}
+	assert.False(t, RawUnknown.keeps("swap", 6), "RawUnknown should not keep decoded row of many params")
+	assert.True(t, RawManyParams.keeps("swap", 6), "RawManyParams should keep decoded row of many params")
+}
+
+func TestRawLogData(t *testing.T) {
+	transfer := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	from := "0x000000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+	decoded := &common.EventLog{
+		Topics: []string{transfer, from, from},
+		Data:   []byte{0x09, 0xc4},
+		Event:  "Transfer",
+		Params: []*common.NamedValue{{Name: "from"}, {Name: "to"}, {Name: "value"}},
+	}
+	unknown := &common.EventLog{
+		Topics: []string{transfer},
+		Data:   []byte{0x01},
+		Event:  "UNKNOWN",
+	}
+	defer SetRawLogPolicy(RawAlways)
+
+	SetRawLogPolicy(RawAlways)
+	topics, data := rawLogData(decoded)
+	assert.Equal(t, []string{transfer[2:], from[2:], from[2:]}, topics, "RawAlways should keep topics of decoded log")
+	assert.Equal(t, "09c4", data, "RawAlways should keep data of decoded log")
+	topics, data = rawLogData(unknown)
+	assert.Equal(t, []string{transfer[2:]}, topics, "RawAlways should keep topics of UNKNOWN log")
+	assert.Equal(t, "01", data, "RawAlways should keep data of UNKNOWN log")
+
+	SetRawLogPolicy(RawUnknown)
+	topics, data = rawLogData(decoded)
+	assert.Empty(t, topics, "RawUnknown should not keep topics of decoded log")
+	assert.Empty(t, data, "RawUnknown should not keep data of decoded log")
+	topics, data = rawLogData(unknown)
+	assert.Equal(t, []string{transfer[2:]}, topics, "RawUnknown should keep topics of UNKNOWN log")
+	assert.Equal(t, "01", data, "RawUnknown should keep data of UNKNOWN log")
+}
@@ -182,9 +182,12 @@ INNER JOIN (
 		Decimals
 	FROM
 		ethdb.contracts
+	WHERE
+		TokenStandard IN ('ERC20', 'ERC4626')
+		-- contracts stored before token standards were classified
+		OR (TokenStandard = '' AND Symbol != '')
 ) AS c ON
-	c.Symbol != ''
-	AND t.Contract = c.Address;
+	t.Contract = c.Address;
 
 -- or use aggregated view
 SELECT
@@ -216,9 +219,12 @@ INNER JOIN (
 		Decimals
 	FROM
 		ethdb.contracts
+	WHERE
+		TokenStandard IN ('ERC20', 'ERC4626')
+		-- contracts stored before token standards were classified
+		OR (TokenStandard = '' AND Symbol != '')
 ) AS c ON
-	c.Symbol != ''
-	AND t.Contract = c.Address;
+	t.Contract = c.Address;
 
 -- Token senders monthly summary
 SELECT
@@ -445,6 +451,24 @@ GROUP BY
 	EvtDate,
 	Contract;
 
+-- daily token transfer amount using decimals-adjusted Amount of events
+SELECT
+	count(*) AS EvtCount,
+	sum(Amount) AS Amount,
+	Address,
+	toDate(BlockTime) AS EvtDate
+FROM
+	ethdb.logs
+WHERE
+	BlockTime >= toDateTime('2021-12-01','UTC')
+	AND BlockTime < addMonths(toDateTime('2021-12-01','UTC'),1)
+	AND Event = 'Transfer'
+	AND Amount > 0
+	AND Removed != 1
+GROUP BY
+	Address,
+	EvtDate;
+
 -- token transfer events by symbol
 SELECT
 	t.EvtCount,
@@ -479,7 +503,9 @@ INNER JOIN (
 	FROM
 		ethdb.contracts
 	WHERE
-		Symbol != ''
+		TokenStandard IN ('ERC20', 'ERC4626')
+		-- contracts stored before token standards were classified
+		OR (TokenStandard = '' AND Symbol != '')
 ) AS c ON
 	t.Address = c.Address;
 
@@ -514,7 +540,9 @@ INNER JOIN (
 	FROM
 		ethdb.contracts
 	WHERE
-		Symbol != ''
+		TokenStandard IN ('ERC20', 'ERC4626')
+		-- contracts stored before token standards were classified
+		OR (TokenStandard = '' AND Symbol != '')
 ) AS c ON
 	t.Contract = c.Address;
 
@@ -671,7 +699,7 @@ select Address, Symbol, Name, Decimals, ABI from ethdb.contracts where Symbol =
 -- daily USDC event summary
 SELECT
 	count(*) AS EvtCount,
-	toInt256(sum(divide(arrayElement(`Params.ValueDouble`, 3), exp10(6)))) as Amount,
+	toInt256(sum(Amount)) as Amount,
 	toDate(BlockTime) as EvtDate
 FROM
 	ethdb.logs
@@ -910,3 +938,17 @@ group by Contract, Sender;
 select * from system.settings where name like 'max_mem%';
 select * from system.processes;
 select * from system.errors;
+
+-- daily USDC transfers using typed table of Transfer events, created with store.SetTypedTables
+SELECT
+	toDate(BlockTime) AS BlockDate,
+	count(*) AS Transfers,
+	sum(toFloat64(value)) / 1e6 AS TotalAmount
+FROM
+	ethdb.Transfer_ddf252ad FINAL
+WHERE
+	Address = 'a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48'
+GROUP BY
+	BlockDate
+ORDER BY
+	BlockDate;
@@ -19,12 +19,36 @@ CREATE TABLE ethdb.contracts
     `Symbol` String NULL,
     `Decimals` UInt8,
     `TotalSupply` Float64,
+    `ExactSupply` UInt256,
     `LastEventDate` Date,
     `LastErrorDate` Date,
-    `ABI` String
+    `ABI` String,
+    `ContractName` String,
+    `Compiler` String,
+    `Optimized` UInt8,
+    `OptimizerRuns` UInt32,
+    `License` String,
+    `IsProxy` UInt8,
+    `Implementation` FixedString(40),
+    `AddressType` Int8,
+    `CodeHash` FixedString(64),
+    `CloneTarget` FixedString(40),
+    `TokenStandard` String
 ) ENGINE = ReplacingMergeTree()
 ORDER BY (Address);
 
+DROP TABLE IF EXISTS ethdb.token_supply;
+CREATE TABLE ethdb.token_supply
+(
+    `Address` FixedString(40),
+    `TotalSupply` Float64,
+    `ExactSupply` UInt256,
+    `BlockNumber` UInt64,
+    `SnapshotTime` DateTime('UTC')
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(SnapshotTime)
+ORDER BY (Address, SnapshotTime);
+
 DROP TABLE IF EXISTS ethdb.blocks;
 CREATE TABLE ethdb.blocks
 (
@@ -33,6 +57,7 @@ CREATE TABLE ethdb.blocks
     `ParentHash` FixedString(64),
     `Miner` FixedString(40),
     `Difficulty` Float64,
+    `ExactDifficulty` UInt256,
     `GasLimit` UInt64,
     `GasUsed` UInt64,
     `Status` Int8,
@@ -51,20 +76,78 @@ CREATE TABLE ethdb.transactions
     `From` FixedString(40),
     `To` FixedString(40),
     `Method` String,
+    `Standard` String,
     `Params` Nested(
         Name String,
         Seq Int8,
         ValueString String,
-        ValueDouble Float64),
+        ValueDouble Float64,
+        ValueUInt256 UInt256,
+        ValueInt256 Int256),
     `GasPrice` UInt64,
     `Gas` UInt64,
     `Value` Float64,
+    `ExactValue` UInt256,
     `Nonce` UInt64,
+    `Input` String CODEC(ZSTD),
     `BlockTime` DateTime('UTC')
 ) ENGINE = CollapsingMergeTree(Status)
 PARTITION BY toYYYYMM(BlockTime)
 ORDER BY (To, BlockTime, Hash);
 
+DROP TABLE IF EXISTS ethdb.calls;
+CREATE TABLE ethdb.calls
+(
+    `TxnHash` FixedString(64),
+    `Path` String,
+    `BlockNumber` UInt64,
+    `From` FixedString(40),
+    `To` FixedString(40),
+    `Value` Float64,
+    `ExactValue` UInt256,
+    `Method` String,
+    `Standard` String,
+    `Params` Nested(
+        Name String,
+        Seq Int8,
+        ValueString String,
+        ValueDouble Float64,
+        ValueUInt256 UInt256,
+        ValueInt256 Int256),
+    `BlockTime` DateTime('UTC')
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(BlockTime)
+ORDER BY (To, BlockTime, TxnHash, Path);
+
+DROP TABLE IF EXISTS ethdb.user_operations;
+CREATE TABLE ethdb.user_operations
+(
+    `TxnHash` FixedString(64),
+    `OpIndex` UInt32,
+    `BlockNumber` UInt64,
+    `EntryPoint` FixedString(40),
+    `Sender` FixedString(40),
+    `Nonce` String,
+    `Factory` String,
+    `Paymaster` String,
+    `Method` String,
+    `Standard` String,
+    `Params` Nested(
+        Name String,
+        Seq Int8,
+        ValueString String,
+        ValueDouble Float64,
+        ValueUInt256 UInt256,
+        ValueInt256 Int256),
+    `UserOpHash` String,
+    `Success` Int8,
+    `ActualGasCost` Float64,
+    `ActualGasUsed` UInt64,
+    `BlockTime` DateTime('UTC')
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(BlockTime)
+ORDER BY (Sender, BlockTime, TxnHash, OpIndex);
+
 DROP TABLE IF EXISTS ethdb.logs;
 CREATE TABLE ethdb.logs
 (
@@ -74,12 +157,18 @@ CREATE TABLE ethdb.logs
     `TxnIndex` UInt64,
     `TxnHash` FixedString(64),
     `Address` FixedString(40),
+    `Topics` Array(FixedString(64)),
+    `Data` String CODEC(ZSTD),
     `Event` String,
+    `Standard` String,
+    `Amount` Float64,
     `Params` Nested(
         Name String,
         Seq Int8,
         ValueString String,
-        ValueDouble Float64),
+        ValueDouble Float64,
+        ValueUInt256 UInt256,
+        ValueInt256 Int256),
     `BlockTime` DateTime('UTC')
 ) ENGINE = CollapsingMergeTree(Removed)
 PARTITION BY toYYYYMM(BlockTime)
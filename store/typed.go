@@ -0,0 +1,350 @@
+package store
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	clickhouse "github.com/mailru/go-clickhouse"
+	"github.com/open-dovetail/eth-track/common"
+	"github.com/open-dovetail/eth-track/contract/standard/erc1155"
+	"github.com/open-dovetail/eth-track/contract/standard/erc165"
+	"github.com/open-dovetail/eth-track/contract/standard/erc20"
+	"github.com/open-dovetail/eth-track/contract/standard/erc2612"
+	"github.com/open-dovetail/eth-track/contract/standard/erc4626"
+	"github.com/open-dovetail/eth-track/contract/standard/erc721"
+	"github.com/open-dovetail/eth-track/contract/standard/erc777"
+	"github.com/open-dovetail/eth-track/contract/standard/weth9"
+	"github.com/pkg/errors"
+	web3 "github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+)
+
+// column of a typed table, with column name and ClickHouse type
+type typedColumn struct {
+	name string
+	typ  string
+}
+
+// fixed columns of typed event tables, followed by one column per event param
+var typedLogColumns = []typedColumn{
+	{"BlockNumber", "UInt64"},
+	{"LogIndex", "UInt64"},
+	{"Removed", "Int8"},
+	{"TxnHash", "FixedString(64)"},
+	{"Address", "FixedString(40)"},
+	{"BlockTime", "DateTime('UTC')"},
+}
+
+// fixed columns of typed method tables, followed by one column per method param
+var typedTransactionColumns = []typedColumn{
+	{"Hash", "FixedString(64)"},
+	{"BlockNumber", "UInt64"},
+	{"Status", "Int8"},
+	{"From", "FixedString(40)"},
+	{"To", "FixedString(40)"},
+	{"BlockTime", "DateTime('UTC')"},
+}
+
+// cache of typed tables that have been created or evolved in the database
+type typedTableCache struct {
+	sync.Mutex
+	targets map[string]bool            // lower-case contract addresses, event topics and method IDs
+	columns map[string]map[string]bool // table name => param columns
+}
+
+var typedTables = &typedTableCache{
+	targets: make(map[string]bool),
+	columns: make(map[string]map[string]bool),
+}
+
+// characters that are not allowed in names of typed tables or columns
+var typedNameRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// canonical param names of standard events and methods keyed by event topic or method ID in hex with prefix 0x,
+// so a typed table has the same columns no matter which ABI decoded its rows.
+var canonicalParams = make(map[string][]string)
+
+func init() {
+	// earlier standard takes precedence for the same signature, e.g., ERC20 Transfer over ERC721 Transfer
+	standards := []*abi.ABI{
+		erc20.ERC20Abi(),
+		erc2612.ERC2612Abi(),
+		erc4626.ERC4626Abi(),
+		weth9.WETH9Abi(),
+		erc165.ERC165Abi(),
+		erc777.ERC777Abi(),
+		erc721.ERC721Abi(),
+		erc1155.ERC1155Abi(),
+	}
+	for _, std := range standards {
+		for _, mth := range std.Methods {
+			addCanonicalParams("0x"+hex.EncodeToString(mth.ID()), mth.Inputs)
+		}
+		for _, evt := range std.Events {
+			addCanonicalParams(evt.ID().String(), evt.Inputs)
+		}
+	}
+}
+
+func addCanonicalParams(signature string, inputs *abi.Type) {
+	if _, ok := canonicalParams[signature]; ok {
+		return
+	}
+	var names []string
+	for _, elem := range inputs.TupleElems() {
+		names = append(names, elem.Name)
+	}
+	canonicalParams[signature] = names
+}
+
+// configure contracts and signatures whose transactions and event logs are also stored in typed tables.
+// a target is a contract address, an event topic or method ID in hex, or a text signature,
+// e.g., "Transfer(address,address,uint256)".
+func SetTypedTables(targets []string) {
+	typedTables.Lock()
+	defer typedTables.Unlock()
+
+	typedTables.targets = make(map[string]bool)
+	for _, t := range targets {
+		t = strings.TrimSpace(t)
+		if len(t) == 0 {
+			continue
+		}
+		if strings.Contains(t, "(") {
+			// text signature of event or method
+			hash := web3.Keccak256([]byte(strings.ReplaceAll(t, " ", "")))
+			typedTables.targets["0x"+hex.EncodeToString(hash)] = true
+			typedTables.targets["0x"+hex.EncodeToString(hash[:4])] = true
+			continue
+		}
+		typedTables.targets[strings.ToLower(t)] = true
+	}
+}
+
+// return true if the contract address or event/method signature is configured for typed tables
+func isTypedTarget(address, signature string) bool {
+	typedTables.Lock()
+	defer typedTables.Unlock()
+
+	if len(typedTables.targets) == 0 {
+		return false
+	}
+	return typedTables.targets[strings.ToLower(address)] || typedTables.targets[strings.ToLower(signature)]
+}
+
+// return name of typed table qualified by the leading 8 hex digits of the signature, e.g., Transfer_ddf252ad.
+// the name does not depend on the standard or contract ABI that decoded the row,
+// so rows of the same signature are stored in the same table.
+func typedTableName(signature, name string) string {
+	qualifier := strings.TrimPrefix(strings.ToLower(signature), "0x")
+	if len(qualifier) > 8 {
+		qualifier = qualifier[:8]
+	}
+	return typedNameRegex.ReplaceAllString(name+"_"+qualifier, "_")
+}
+
+// return typed columns and values of top-level params, which are mapped to columns by position.
+// columns are named by the canonical param names of a standard signature, or arg0, arg1, etc. otherwise,
+// so contracts of different param names for the same signature do not add columns to the same table.
+// flattened components are skipped because their tuple or array params are stored as JSON.
+func typedParams(fixed []typedColumn, signature string, params []*common.NamedValue) ([]typedColumn, []interface{}) {
+	used := make(map[string]bool)
+	for _, c := range fixed {
+		used[c.name] = true
+	}
+	canonical := canonicalParams[strings.ToLower(signature)]
+	var columns []typedColumn
+	var values []interface{}
+	for _, p := range params {
+		if strings.ContainsAny(p.Name, ".[") {
+			continue
+		}
+		pos := len(columns)
+		name := fmt.Sprintf("arg%d", pos)
+		if pos < len(canonical) && len(canonical[pos]) > 0 {
+			name = typedNameRegex.ReplaceAllString(canonical[pos], "_")
+		}
+		for used[name] {
+			name += "_"
+		}
+		used[name] = true
+		typ, value := typedValue(p)
+		columns = append(columns, typedColumn{name, typ})
+		values = append(values, value)
+	}
+	return columns, values
+}
+
+// return ClickHouse column type and insert value of a param
+func typedValue(p *common.NamedValue) (string, interface{}) {
+	switch p.Kind {
+	case abi.KindAddress:
+		if addr, ok := p.Value.(web3.Address); ok {
+			return "FixedString(40)", hexToFixedString(strings.ToLower(addr.String()), 40)
+		}
+		return "FixedString(40)", ""
+	case abi.KindBool:
+		b, _ := p.Value.(bool)
+		return "UInt8", boolToUInt8(b)
+	case abi.KindUInt:
+		switch v := p.Value.(type) {
+		case uint8:
+			return "UInt64", clickhouse.UInt64(uint64(v))
+		case uint16:
+			return "UInt64", clickhouse.UInt64(uint64(v))
+		case uint32:
+			return "UInt64", clickhouse.UInt64(uint64(v))
+		case uint64:
+			return "UInt64", clickhouse.UInt64(v)
+		case *big.Int:
+			return "UInt256", bigIntToDecimal(v)
+		}
+	case abi.KindInt:
+		switch v := p.Value.(type) {
+		case int8:
+			return "Int64", int64(v)
+		case int16:
+			return "Int64", int64(v)
+		case int32:
+			return "Int64", int64(v)
+		case int64:
+			return "Int64", v
+		case *big.Int:
+			return "Int256", bigIntToDecimal(v)
+		}
+	case abi.KindString:
+		s, _ := p.Value.(string)
+		return "String", s
+	}
+
+	// bytes are stored as hex, and tuples or arrays as JSON
+	value := hexEncodeUint8Array(p.Value)
+	if s, ok := value.(string); ok {
+		return "String", s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		glog.Warningf("Failed to serialize param %s %T: %v", p.Name, p.Value, err)
+		return "String", ""
+	}
+	return "String", string(data)
+}
+
+// create a typed table if it does not exist, and add new param columns if params differ from a previous ABI version.
+// type of existing columns is not changed.
+func (c *ClickHouseConnection) evolveTypedTable(table string, fixed, columns []typedColumn, engine string) error {
+	typedTables.Lock()
+	defer typedTables.Unlock()
+
+	known, ok := typedTables.columns[table]
+	if !ok {
+		var defs []string
+		for _, col := range append(append([]typedColumn{}, fixed...), columns...) {
+			defs = append(defs, fmt.Sprintf("`%s` %s", col.name, col.typ))
+		}
+		sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` (%s) %s", table, strings.Join(defs, ", "), engine)
+		glog.Info("Execute sql: ", sql)
+		if _, err := c.connection.Exec(sql); err != nil {
+			return errors.Wrapf(err, "Failed to create typed table %s", table)
+		}
+		// table may exist with columns of a previous ABI version, so add missing columns
+		known = make(map[string]bool)
+		typedTables.columns[table] = known
+	}
+	for _, col := range columns {
+		if known[col.name] {
+			continue
+		}
+		sql := fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN IF NOT EXISTS `%s` %s", table, col.name, col.typ)
+		glog.Info("Execute sql: ", sql)
+		if _, err := c.connection.Exec(sql); err != nil {
+			return errors.Wrapf(err, "Failed to add column %s to typed table %s", col.name, table)
+		}
+		known[col.name] = true
+	}
+	return nil
+}
+
+// insert a row into a typed table, and prepare the insert statement for the column set if necessary
+func (t *ClickHouseTransaction) insertTypedRow(table string, fixed, columns []typedColumn, values []interface{}) error {
+	var names []string
+	for _, col := range append(append([]typedColumn{}, fixed...), columns...) {
+		names = append(names, "`"+col.name+"`")
+	}
+	key := "typed:" + table + ":" + strings.Join(names, ",")
+	stmt, ok := t.stmts[key]
+	if !ok {
+		var err error
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+		if stmt, err = t.tx.Prepare(fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)", table, strings.Join(names, ", "), placeholders)); err != nil {
+			return err
+		}
+		t.stmts[key] = stmt
+	}
+	_, err := stmt.Exec(values...)
+	return err
+}
+
+// store a decoded event log in its typed table, if the contract or event is configured for typed tables
+func (t *ClickHouseTransaction) insertTypedLog(eventlog *common.EventLog) error {
+	if len(eventlog.Event) == 0 || eventlog.Event == "UNKNOWN" || len(eventlog.Topics) == 0 {
+		return nil
+	}
+	if !isTypedTarget(eventlog.Address, eventlog.Topics[0]) {
+		return nil
+	}
+	table := typedTableName(eventlog.Topics[0], eventlog.Event)
+	columns, values := typedParams(typedLogColumns, eventlog.Topics[0], eventlog.Params)
+	if err := db.evolveTypedTable(table, typedLogColumns, columns,
+		"ENGINE = CollapsingMergeTree(Removed) PARTITION BY toYYYYMM(BlockTime) ORDER BY (Address, BlockTime, BlockNumber, LogIndex)"); err != nil {
+		return err
+	}
+	var removed = int8(-1)
+	if eventlog.Removed {
+		removed = 1
+	}
+	return t.insertTypedRow(table, typedLogColumns, columns, append([]interface{}{
+		clickhouse.UInt64(eventlog.BlockNumber),
+		clickhouse.UInt64(eventlog.LogIndex),
+		removed,
+		hexToFixedString(eventlog.TxnHash, 64),
+		hexToFixedString(eventlog.Address, 40),
+		secondsToDateTime(eventlog.BlockTime),
+	}, values...))
+}
+
+// store a decoded transaction in its typed table, if the contract or method is configured for typed tables.
+// names of typed method tables start with tx_, e.g., tx_transfer_a9059cbb.
+func (t *ClickHouseTransaction) insertTypedTransaction(transaction *common.Transaction) error {
+	if len(transaction.Method) == 0 || transaction.Method == "UNKNOWN" || len(transaction.Input) < 4 {
+		return nil
+	}
+	methodID := "0x" + hex.EncodeToString(transaction.Input[:4])
+	if !isTypedTarget(transaction.To, methodID) {
+		return nil
+	}
+	table := "tx_" + typedTableName(methodID, transaction.Method)
+	columns, values := typedParams(typedTransactionColumns, methodID, transaction.Params)
+	if err := db.evolveTypedTable(table, typedTransactionColumns, columns,
+		"ENGINE = CollapsingMergeTree(Status) PARTITION BY toYYYYMM(BlockTime) ORDER BY (To, BlockTime, Hash)"); err != nil {
+		return err
+	}
+	var status = int8(-1)
+	if transaction.Status {
+		status = 1
+	}
+	return t.insertTypedRow(table, typedTransactionColumns, columns, append([]interface{}{
+		hexToFixedString(transaction.Hash, 64),
+		clickhouse.UInt64(transaction.BlockNumber),
+		status,
+		hexToFixedString(transaction.From, 40),
+		hexToFixedString(transaction.To, 40),
+		secondsToDateTime(transaction.BlockTime),
+	}, values...))
+}